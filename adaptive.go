@@ -0,0 +1,28 @@
+package queue
+
+// adaptiveCapacity holds the peak lengths used by adaptive preallocation.
+type adaptiveCapacity struct {
+	// The peak length since the last Reset.
+	peak int
+
+	// The peak length before the last Reset, used as the capacity of the next growth.
+	hint int
+}
+
+// EnableAdaptiveCapacity makes the queue remember its peak length when Reset is called,
+// and allocate a buffer large enough for that length on the next growth.
+// In a steady-state loop that fills and resets the queue every iteration,
+// this replaces the repeated growth from zero with a single allocation.
+// Only the last cycle is remembered, so the capacity follows the workload when it shrinks.
+// Calling EnableAdaptiveCapacity again has no effect.
+func (q *Queue[T]) EnableAdaptiveCapacity() {
+	if e := q.ensureExtensions(); e.adaptive == nil {
+		e.adaptive = &adaptiveCapacity{peak: q.length}
+	}
+}
+
+// reset starts a new cycle, remembering the peak length of the last one.
+func (a *adaptiveCapacity) reset() {
+	a.hint = a.peak
+	a.peak = 0
+}
@@ -0,0 +1,44 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestQueue_EnableAdaptiveCapacity(t *testing.T) {
+	// Setup
+	var q queue.Queue[int]
+	q.EnableAdaptiveCapacity()
+	grows := 0
+	q.OnGrow(func(oldCap, newCap int) { grows++ })
+
+	// Exercise
+	for i := range 100 {
+		q.Push(i)
+	}
+	q.Reset()
+	grows = 0
+	for i := range 100 {
+		q.Push(i)
+	}
+
+	// Verify
+	if grows != 1 || q.Cap() != 128 {
+		t.Errorf("grows = %v, Cap() = %v after Reset; want 1, 128", grows, q.Cap())
+	}
+
+	// Exercise
+	// A smaller cycle lowers the capacity for the next one.
+	q.Reset()
+	for i := range 10 {
+		q.Push(i)
+	}
+	q.Reset()
+	q.Push(0)
+
+	// Verify
+	if q.Cap() != 16 {
+		t.Errorf("Cap() = %v after a smaller cycle; want 16", q.Cap())
+	}
+}
@@ -0,0 +1,100 @@
+package queue
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Aggregator collects individually pushed elements into batches and pushes each batch
+// to an output queue when it reaches a maximum size or a maximum age, whichever comes first.
+// The age of a batch is measured from its first element.
+// It complements the pop-side batching of SyncQueue.PopBatch on the producer side.
+// Aggregator is safe for concurrent use by multiple goroutines.
+type Aggregator[T any] struct {
+	out     *SyncQueue[[]T]
+	maxSize int
+	maxAge  time.Duration
+
+	mu     sync.Mutex
+	batch  []T
+	timer  *time.Timer
+	closed bool
+
+	// The generation of the current batch, which lets a timer of an already flushed batch do nothing.
+	generation uint64
+}
+
+// NewAggregator returns an aggregator that pushes batches of up to maxSize elements to out.
+// If maxAge is positive, a batch is also pushed maxAge after its first element was pushed.
+// If maxSize is not positive, it panics.
+func NewAggregator[T any](out *SyncQueue[[]T], maxSize int, maxAge time.Duration) *Aggregator[T] {
+	if maxSize <= 0 {
+		panic(fmt.Sprintf("queue: batch size must be positive: %d", maxSize))
+	}
+	return &Aggregator[T]{out: out, maxSize: maxSize, maxAge: maxAge}
+}
+
+// Push adds an element to the current batch, and pushes the batch to the output queue
+// if it has reached the maximum size. If the aggregator or the output queue is closed,
+// Push returns ErrClosed.
+func (a *Aggregator[T]) Push(x T) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return ErrClosed
+	}
+
+	a.batch = append(a.batch, x)
+	if len(a.batch) >= a.maxSize {
+		return a.flushLocked()
+	}
+	if len(a.batch) == 1 && a.maxAge > 0 {
+		generation := a.generation
+		a.timer = time.AfterFunc(a.maxAge, func() {
+			a.mu.Lock()
+			defer a.mu.Unlock()
+			if a.generation == generation {
+				// The error is reported by the next Push.
+				a.flushLocked()
+			}
+		})
+	}
+	return nil
+}
+
+// Flush pushes the current batch to the output queue immediately, if it is not empty.
+func (a *Aggregator[T]) Flush() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.flushLocked()
+}
+
+// Close flushes the current batch and stops the aggregator. Subsequent pushes fail with ErrClosed.
+// The output queue is not closed.
+func (a *Aggregator[T]) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.closed = true
+	return a.flushLocked()
+}
+
+// flushLocked pushes the current batch to the output queue. Caller must hold mu.
+func (a *Aggregator[T]) flushLocked() error {
+	if len(a.batch) == 0 {
+		return nil
+	}
+	batch := a.batch
+	// The batch is owned by the output queue from now on.
+	a.batch = nil
+	a.generation++
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+	err := a.out.Push(batch)
+	if err != nil {
+		a.closed = true
+	}
+	return err
+}
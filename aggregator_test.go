@@ -0,0 +1,61 @@
+package queue_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestAggregator_MaxSize(t *testing.T) {
+	// Setup
+	var out queue.SyncQueue[[]int]
+	a := queue.NewAggregator(&out, 3, 0)
+
+	// Exercise
+	for i := range 7 {
+		if err := a.Push(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify
+	var batches [][]int
+	for {
+		b, ok := out.Pop()
+		if !ok {
+			break
+		}
+		batches = append(batches, b)
+	}
+	if want := [][]int{{0, 1, 2}, {3, 4, 5}, {6}}; !slices.EqualFunc(batches, want, slices.Equal) {
+		t.Errorf("batches = %v; want %v", batches, want)
+	}
+	if err := a.Push(7); err != queue.ErrClosed {
+		t.Errorf("Push() after Close = %v; want %v", err, queue.ErrClosed)
+	}
+}
+
+func TestAggregator_MaxAge(t *testing.T) {
+	// Setup
+	var out queue.SyncQueue[[]int]
+	a := queue.NewAggregator(&out, 100, 10*time.Millisecond)
+	defer a.Close()
+
+	// Exercise
+	a.Push(1)
+	a.Push(2)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	batch, err := out.PopWait(ctx)
+
+	// Verify
+	if err != nil || !slices.Equal(batch, []int{1, 2}) {
+		t.Errorf("PopWait() = (%v, %v); want ([1 2], nil)", batch, err)
+	}
+}
@@ -0,0 +1,39 @@
+package queue
+
+// Allocator allocates and releases the buffers of queues.
+// It allows queues to take their buffers from arenas, pools or allocation trackers.
+type Allocator[T any] interface {
+	// Alloc returns a slice of length n. n is a power of 2.
+	Alloc(n int) []T
+
+	// Free releases a buffer previously returned by Alloc
+	// that the queue no longer uses.
+	Free(buffer []T)
+}
+
+// UseAllocator makes the queue allocate and release its buffers with a.
+// Buffers allocated before the call are released with a as well.
+// Passing nil restores the default, which allocates buffers with make
+// and leaves them to the garbage collector.
+func (q *Queue[T]) UseAllocator(a Allocator[T]) {
+	if a == nil && q.ext == nil {
+		return
+	}
+	q.ensureExtensions().allocator = a
+}
+
+// alloc returns a new buffer of the given capacity, which must be a power of 2 or zero.
+func (q *Queue[T]) alloc(capacity int) []T {
+	if q.ext == nil || q.ext.allocator == nil || capacity == 0 {
+		return make([]T, capacity)
+	}
+	return q.ext.allocator.Alloc(capacity)
+}
+
+// free releases a buffer that the queue no longer uses.
+func (q *Queue[T]) free(buffer []T) {
+	if q.ext == nil || q.ext.allocator == nil || len(buffer) == 0 {
+		return
+	}
+	q.ext.allocator.Free(buffer)
+}
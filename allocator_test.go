@@ -0,0 +1,44 @@
+package queue_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+// trackingAllocator records the sizes of allocated and released buffers.
+type trackingAllocator struct {
+	allocs []int
+	frees  []int
+}
+
+func (a *trackingAllocator) Alloc(n int) []int {
+	a.allocs = append(a.allocs, n)
+	return make([]int, n)
+}
+
+func (a *trackingAllocator) Free(buffer []int) {
+	a.frees = append(a.frees, len(buffer))
+}
+
+func TestQueue_UseAllocator(t *testing.T) {
+	// Setup
+	var a trackingAllocator
+	var q queue.Queue[int]
+	q.UseAllocator(&a)
+
+	// Exercise
+	q.PushMany([]int{3, 1, 4})
+	q.Push(1)
+	q.Push(5)
+	q.Reset()
+
+	// Verify
+	if expected := []int{4, 8}; !slices.Equal(a.allocs, expected) {
+		t.Errorf("allocs: %v; want: %v", a.allocs, expected)
+	}
+	if expected := []int{4, 8}; !slices.Equal(a.frees, expected) {
+		t.Errorf("frees: %v; want: %v", a.frees, expected)
+	}
+}
@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var errMalformedBinary = errors.New("queue: malformed binary encoding")
+
+// MarshalBinary implements encoding.BinaryMarshaler when T implements it.
+// The encoding is the number of elements followed by the encodings of the elements
+// in FIFO order, each prefixed with its length, where the numbers are uvarints.
+// If T does not implement encoding.BinaryMarshaler, MarshalBinary returns an error.
+func (q *Queue[T]) MarshalBinary() ([]byte, error) {
+	// Check the type rather than the elements so that the result does not depend on Len().
+	elemType := reflect.TypeFor[T]()
+	if !elemType.Implements(reflect.TypeFor[encoding.BinaryMarshaler]()) {
+		return nil, fmt.Errorf("queue: %v does not implement encoding.BinaryMarshaler", elemType)
+	}
+
+	data := binary.AppendUvarint(nil, uint64(q.length))
+	for i := range q.length {
+		m, ok := any(q.buffer[q.wrap(q.head+i)]).(encoding.BinaryMarshaler)
+		if !ok {
+			// T is an interface type and the element is nil.
+			return nil, fmt.Errorf("queue: nil element at index %d", i)
+		}
+		b, err := m.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = binary.AppendUvarint(data, uint64(len(b)))
+		data = append(data, b...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler when *T implements it.
+// It replaces the elements of the queue with the elements decoded from data,
+// which must have been produced by MarshalBinary. If decoding fails, the queue is not modified.
+// If *T does not implement encoding.BinaryUnmarshaler, UnmarshalBinary returns an error.
+func (q *Queue[T]) UnmarshalBinary(data []byte) error {
+	ptrType := reflect.TypeFor[*T]()
+	if !ptrType.Implements(reflect.TypeFor[encoding.BinaryUnmarshaler]()) {
+		return fmt.Errorf("queue: %v does not implement encoding.BinaryUnmarshaler", ptrType)
+	}
+
+	n, k := binary.Uvarint(data)
+	if k <= 0 || n > uint64(len(data)) {
+		// Each element takes at least one byte for its length.
+		return errMalformedBinary
+	}
+	data = data[k:]
+
+	elements := make([]T, n)
+	for i := range elements {
+		u := any(&elements[i]).(encoding.BinaryUnmarshaler)
+		size, k := binary.Uvarint(data)
+		if k <= 0 || size > uint64(len(data)-k) {
+			return errMalformedBinary
+		}
+		data = data[k:]
+		if err := u.UnmarshalBinary(data[:size]); err != nil {
+			return err
+		}
+		data = data[size:]
+	}
+	if len(data) != 0 {
+		return errMalformedBinary
+	}
+
+	q.Truncate(0)
+	q.PushMany(elements)
+	return nil
+}
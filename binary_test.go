@@ -0,0 +1,103 @@
+package queue_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+// record is a fixed-size element implementing encoding.BinaryMarshaler and BinaryUnmarshaler.
+type record struct {
+	id uint32
+}
+
+func (r record) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint32(nil, r.id), nil
+}
+
+func (r *record) UnmarshalBinary(data []byte) error {
+	if len(data) != 4 {
+		return errors.New("record: invalid length")
+	}
+	r.id = binary.BigEndian.Uint32(data)
+	return nil
+}
+
+func TestQueue_MarshalBinary(t *testing.T) {
+	for _, n := range []int{0, 1, 7} {
+		t.Run(fmt.Sprintf("n=%d", n), func(t *testing.T) {
+			// Setup
+			var q queue.Queue[record]
+			for range 5 {
+				q.Push(record{})
+				q.Pop()
+			}
+			for i := range n {
+				q.Push(record{id: uint32(i * 100)})
+			}
+
+			// Exercise
+			data, err := q.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded queue.Queue[record]
+			decoded.Push(record{id: 999})
+			err = decoded.UnmarshalBinary(data)
+
+			// Verify
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual, expected := slices.Collect(decoded.All()), slices.Collect(q.All()); !slices.Equal(actual, expected) {
+				t.Errorf("decoded: %v; want: %v", actual, expected)
+			}
+		})
+	}
+}
+
+func TestQueue_UnmarshalBinary_malformed(t *testing.T) {
+	var q queue.Queue[record]
+	q.Push(record{id: 1})
+	q.Push(record{id: 2})
+	data, _ := q.MarshalBinary()
+
+	for _, bad := range [][]byte{nil, data[:len(data)-1], append(slices.Clone(data), 0)} {
+		decoded := newRecordQueue(record{id: 42})
+		if err := decoded.UnmarshalBinary(bad); err == nil {
+			t.Errorf("UnmarshalBinary(%v) succeeded; want an error", bad)
+		}
+		if decoded.Len() != 1 {
+			t.Errorf("UnmarshalBinary(%v) modified the queue on failure", bad)
+		}
+	}
+}
+
+func TestQueue_MarshalBinary_unsupported(t *testing.T) {
+	for _, n := range []int{0, 3} {
+		t.Run(fmt.Sprintf("n=%d", n), func(t *testing.T) {
+			var q queue.Queue[int]
+			for i := range n {
+				q.Push(i)
+			}
+			if _, err := q.MarshalBinary(); err == nil {
+				t.Errorf("MarshalBinary() of Queue[int] succeeded; want an error")
+			}
+			if err := q.UnmarshalBinary([]byte{0}); err == nil {
+				t.Errorf("UnmarshalBinary() of Queue[int] succeeded; want an error")
+			}
+		})
+	}
+}
+
+func newRecordQueue(records ...record) *queue.Queue[record] {
+	var q queue.Queue[record]
+	for _, r := range records {
+		q.Push(r)
+	}
+	return &q
+}
@@ -0,0 +1,112 @@
+package queue
+
+import "slices"
+
+// Broadcast is a fan-out queue whose elements are delivered to every subscriber.
+// Each subscriber has its own read cursor over a single shared buffer,
+// and an element is released when all subscribers have read it.
+// Elements pushed while there are no subscribers are discarded immediately.
+// The zero value for Broadcast is an empty queue ready to use.
+// Broadcast is NOT safe for concurrent use.
+type Broadcast[T any] struct {
+	queue Queue[T]
+
+	// The sequence number of the first element in queue.
+	// The elements are numbered from zero in the order they were pushed.
+	base uint64
+
+	subscribers []*Subscriber[T]
+}
+
+// Subscriber is a consumer of a Broadcast with its own read cursor.
+type Subscriber[T any] struct {
+	broadcast *Broadcast[T]
+
+	// The sequence number of the next element to read.
+	// Invariant: broadcast.base <= next <= broadcast.base + broadcast.queue.Len()
+	next uint64
+}
+
+// Len returns the number of elements retained for the subscribers,
+// which is the number of unread elements of the slowest subscriber.
+func (b *Broadcast[T]) Len() int {
+	return b.queue.Len()
+}
+
+// Subscribe registers a new subscriber. The subscriber receives the elements
+// pushed after this call.
+func (b *Broadcast[T]) Subscribe() *Subscriber[T] {
+	s := &Subscriber[T]{broadcast: b, next: b.base + uint64(b.queue.Len())}
+	b.subscribers = append(b.subscribers, s)
+	return s
+}
+
+// Push adds an element to be delivered to every subscriber.
+func (b *Broadcast[T]) Push(x T) {
+	if len(b.subscribers) == 0 {
+		b.base++
+		return
+	}
+	b.queue.Push(x)
+}
+
+// release discards the elements that all subscribers have read.
+func (b *Broadcast[T]) release() {
+	end := b.base + uint64(b.queue.Len())
+	for _, s := range b.subscribers {
+		end = min(end, s.next)
+	}
+	if n := int(end - b.base); n > 0 {
+		b.queue.consume(n)
+		b.base = end
+	}
+}
+
+// Len returns the number of elements the subscriber has not read yet.
+func (s *Subscriber[T]) Len() int {
+	b := s.broadcast
+	return int(b.base + uint64(b.queue.Len()) - s.next)
+}
+
+// Pop reads the next element for the subscriber.
+// If the subscriber has read all elements, Pop returns the zero value of T and false.
+func (s *Subscriber[T]) Pop() (T, bool) {
+	if s.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+
+	b := s.broadcast
+	x := b.queue.At(int(s.next - b.base))
+	s.next++
+	if s.next-1 == b.base {
+		b.release()
+	}
+	return x, true
+}
+
+// Peek returns the next element for the subscriber without reading it.
+// If the subscriber has read all elements, Peek returns the zero value of T and false.
+func (s *Subscriber[T]) Peek() (T, bool) {
+	if s.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	b := s.broadcast
+	return b.queue.At(int(s.next - b.base)), true
+}
+
+// Unsubscribe unregisters the subscriber. The elements retained only for it are released.
+// The subscriber must not be used after Unsubscribe.
+func (s *Subscriber[T]) Unsubscribe() {
+	b := s.broadcast
+	if i := slices.Index(b.subscribers, s); i >= 0 {
+		b.subscribers = slices.Delete(b.subscribers, i, i+1)
+		if len(b.subscribers) == 0 {
+			b.base += uint64(b.queue.Len())
+			b.queue.Truncate(0)
+			return
+		}
+		b.release()
+	}
+}
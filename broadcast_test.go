@@ -0,0 +1,64 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestBroadcast(t *testing.T) {
+	var b queue.Broadcast[int]
+	b.Push(0) // discarded because there are no subscribers
+
+	s1 := b.Subscribe()
+	b.Push(1)
+	b.Push(2)
+	s2 := b.Subscribe()
+	b.Push(3)
+
+	popAll := func(s *queue.Subscriber[int]) []int {
+		var xs []int
+		for {
+			x, ok := s.Pop()
+			if !ok {
+				return xs
+			}
+			xs = append(xs, x)
+		}
+	}
+
+	if s1.Len() != 3 || s2.Len() != 1 || b.Len() != 3 {
+		t.Errorf("s1.Len() = %v, s2.Len() = %v, b.Len() = %v; want 3, 1, 3", s1.Len(), s2.Len(), b.Len())
+	}
+
+	if x, ok := s1.Pop(); x != 1 || !ok {
+		t.Errorf("s1.Pop() = %v, %v; want %v, %v", x, ok, 1, true)
+	}
+	if b.Len() != 2 {
+		t.Errorf("b.Len() = %v; want %v", b.Len(), 2)
+	}
+
+	if xs := popAll(s2); len(xs) != 1 || xs[0] != 3 {
+		t.Errorf("s2 read %v; want %v", xs, []int{3})
+	}
+	if b.Len() != 2 { // s1 has not read 2 and 3 yet
+		t.Errorf("b.Len() = %v; want %v", b.Len(), 2)
+	}
+
+	if xs := popAll(s1); len(xs) != 2 || xs[0] != 2 || xs[1] != 3 {
+		t.Errorf("s1 read %v; want %v", xs, []int{2, 3})
+	}
+	if b.Len() != 0 {
+		t.Errorf("b.Len() = %v; want %v", b.Len(), 0)
+	}
+
+	b.Push(4)
+	s2.Unsubscribe()
+	if x, ok := s1.Peek(); x != 4 || !ok {
+		t.Errorf("s1.Peek() = %v, %v; want %v, %v", x, ok, 4, true)
+	}
+	s1.Unsubscribe()
+	if b.Len() != 0 {
+		t.Errorf("b.Len() = %v; want %v", b.Len(), 0)
+	}
+}
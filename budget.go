@@ -0,0 +1,85 @@
+package queue
+
+import "iter"
+
+// BudgetQueue is a FIFO queue bounded by the total cost of its elements
+// instead of their number. The cost of each element is computed by a user-provided
+// function, for example the size of the element in bytes.
+// BudgetQueue is NOT safe for concurrent use.
+type BudgetQueue[T any] struct {
+	queue  Queue[T]
+	size   func(T) int
+	budget int
+
+	// The total cost of the elements in the queue.
+	// Invariant: 0 <= cost <= budget
+	cost int
+}
+
+// NewBudgetQueue returns an empty queue that holds elements whose total cost
+// computed by size does not exceed budget. size must return a non-negative value
+// and must return the same value for the same element while it is in the queue.
+func NewBudgetQueue[T any](budget int, size func(T) int) *BudgetQueue[T] {
+	return &BudgetQueue[T]{size: size, budget: budget}
+}
+
+// Len returns the number of elements in the queue.
+func (q *BudgetQueue[T]) Len() int {
+	return q.queue.Len()
+}
+
+// IsEmpty returns true if the queue is empty.
+func (q *BudgetQueue[T]) IsEmpty() bool {
+	return q.queue.IsEmpty()
+}
+
+// Cost returns the total cost of the elements in the queue.
+func (q *BudgetQueue[T]) Cost() int {
+	return q.cost
+}
+
+// Budget returns the maximum total cost of the elements in the queue.
+func (q *BudgetQueue[T]) Budget() int {
+	return q.budget
+}
+
+// AsFIFO returns an adapter of q that implements FIFO.
+// Its Push silently drops an element whose cost does not fit in the remaining budget,
+// as a drop-tail bounded queue does.
+func (q *BudgetQueue[T]) AsFIFO() FIFO[T] {
+	return droppingFIFO[T]{q}
+}
+
+// Push adds an element to the back of the queue if its cost fits in the remaining budget.
+// It returns false without adding the element otherwise.
+func (q *BudgetQueue[T]) Push(x T) bool {
+	c := q.size(x)
+	if c > q.budget-q.cost {
+		return false
+	}
+	q.queue.Push(x)
+	q.cost += c
+	return true
+}
+
+// Pop removes and returns the element at the front of the queue.
+// If the queue is empty, Pop returns the zero value of T and false.
+func (q *BudgetQueue[T]) Pop() (T, bool) {
+	x, ok := q.queue.Pop()
+	if ok {
+		q.cost -= q.size(x)
+	}
+	return x, ok
+}
+
+// Peek returns the element at the front of the queue without removing it.
+// If the queue is empty, Peek returns the zero value of T and false.
+func (q *BudgetQueue[T]) Peek() (T, bool) {
+	return q.queue.Peek()
+}
+
+// All returns an iterator over all elements in the queue.
+// Do not modify the queue while iterating.
+func (q *BudgetQueue[T]) All() iter.Seq[T] {
+	return q.queue.All()
+}
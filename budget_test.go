@@ -0,0 +1,39 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestBudgetQueue(t *testing.T) {
+	q := queue.NewBudgetQueue(10, func(s string) int { return len(s) })
+
+	for _, step := range []struct {
+		s        string
+		expected bool
+	}{
+		{s: "abcd", expected: true},
+		{s: "efghi", expected: true},
+		{s: "jk", expected: false}, // would exceed the budget
+		{s: "l", expected: true},
+		{s: "", expected: true},
+	} {
+		if actual := q.Push(step.s); actual != step.expected {
+			t.Errorf("Push(%q) = %v; want %v", step.s, actual, step.expected)
+		}
+	}
+	if q.Cost() != 10 || q.Len() != 4 {
+		t.Errorf("Cost() = %v, Len() = %v; want %v, %v", q.Cost(), q.Len(), 10, 4)
+	}
+
+	if s, ok := q.Pop(); s != "abcd" || !ok {
+		t.Errorf("Pop() = %q, %v; want %q, %v", s, ok, "abcd", true)
+	}
+	if q.Cost() != 6 {
+		t.Errorf("Cost() = %v; want %v", q.Cost(), 6)
+	}
+	if !q.Push("jk") {
+		t.Errorf("Push(%q) = false after Pop; want true", "jk")
+	}
+}
@@ -0,0 +1,87 @@
+package queue
+
+import (
+	"errors"
+	"io"
+)
+
+// minRead is the minimum free space that ReadFrom provides to each Read call.
+const minRead = 512
+
+var errNegativeRead = errors.New("queue: reader returned negative count from Read")
+
+// ByteQueue is a queue of bytes that implements io.Reader and io.Writer.
+// Write pushes bytes to the back and Read pops them from the front, so it works as
+// a growable ring pipe for a single goroutine. Unlike bytes.Buffer, it also provides
+// all the methods of Queue, such as At and Peek.
+// The zero value for ByteQueue is an empty queue ready to use.
+// ByteQueue is NOT safe for concurrent use.
+type ByteQueue struct {
+	Queue[byte]
+}
+
+// Write appends the contents of p to the queue. It always returns len(p), nil.
+func (b *ByteQueue) Write(p []byte) (int, error) {
+	b.PushMany(p)
+	return len(p), nil
+}
+
+// Read removes up to len(p) bytes from the front of the queue into p
+// and returns the number of bytes read. If the queue is empty,
+// Read returns io.EOF unless len(p) is zero.
+func (b *ByteQueue) Read(p []byte) (int, error) {
+	if b.IsEmpty() {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	return b.popInto(p), nil
+}
+
+// ReadFrom reads data from r until EOF and appends it to the queue.
+// The data is read directly into the free space of the buffer, which grows as needed.
+// It returns the number of bytes read. Any error except io.EOF is also returned.
+func (b *ByteQueue) ReadFrom(r io.Reader) (int64, error) {
+	var total int64
+	for {
+		if b.remainingCapacity() < minRead {
+			b.reserve(b.length + minRead)
+		}
+		n, err := r.Read(b.tailSegment())
+		if n < 0 {
+			panic(errNegativeRead)
+		}
+		b.extend(n)
+		total += int64(n)
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// WriteTo writes the contents of the queue to w until the queue is empty or an error occurs.
+// The data is written directly from the buffer, with at most two Write calls
+// when w accepts everything. It returns the number of bytes written.
+func (b *ByteQueue) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for !b.IsEmpty() {
+		segment, _ := b.segments()
+		n, err := w.Write(segment)
+		if n < 0 || n > len(segment) {
+			panic("queue: invalid Write count")
+		}
+		b.consume(n)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+		if n < len(segment) {
+			return total, io.ErrShortWrite
+		}
+	}
+	return total, nil
+}
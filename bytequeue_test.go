@@ -0,0 +1,74 @@
+package queue_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestByteQueue(t *testing.T) {
+	var b queue.ByteQueue
+	p := make([]byte, 4)
+
+	// Write and read across the end of the buffer several times.
+	for _, step := range []struct {
+		write string
+		read  string
+	}{
+		{write: "hello", read: "hell"},
+		{write: "world", read: "owor"},
+		{write: "ring", read: "ldri"},
+		{write: "pipe", read: "ngpi"},
+	} {
+		if n, err := io.WriteString(&b, step.write); n != len(step.write) || err != nil {
+			t.Fatalf("WriteString(%q) = %v, %v; want %v, nil", step.write, n, err, len(step.write))
+		}
+		if n, err := b.Read(p); err != nil || string(p[:n]) != step.read {
+			t.Errorf("Read() = %q, %v; want %q, nil", p[:n], err, step.read)
+		}
+	}
+
+	rest, err := io.ReadAll(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rest) != "pe" {
+		t.Errorf("ReadAll() = %q; want %q", rest, "pe")
+	}
+	if n, err := b.Read(p); n != 0 || err != io.EOF {
+		t.Errorf("Read() on empty queue = %v, %v; want 0, EOF", n, err)
+	}
+}
+
+func TestByteQueue_ReadFromWriteTo(t *testing.T) {
+	// Setup
+	// Move the head near the end of the buffer so that the data wraps around.
+	var b queue.ByteQueue
+	b.Write(make([]byte, 2048))
+	b.Read(make([]byte, 2046))
+	b.Write([]byte("89"))
+	data := strings.Repeat("queue-go ", 1000)
+
+	// Exercise
+	n, err := b.ReadFrom(strings.NewReader(data))
+	if n != int64(len(data)) || err != nil {
+		t.Fatalf("ReadFrom() = %v, %v; want %v, nil", n, err, len(data))
+	}
+	var w bytes.Buffer
+	m, err := b.WriteTo(&w)
+
+	// Verify
+	expected := "\x00\x0089" + data
+	if m != int64(len(expected)) || err != nil {
+		t.Errorf("WriteTo() = %v, %v; want %v, nil", m, err, len(expected))
+	}
+	if w.String() != expected {
+		t.Errorf("written data differs: len=%v; want len=%v", w.Len(), len(expected))
+	}
+	if !b.IsEmpty() {
+		t.Errorf("Len() = %v; want 0", b.Len())
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !queuedebug
+
+package queue
+
+// debug enables invariant checking after every modifying operation.
+const debug = false
@@ -0,0 +1,89 @@
+package queue
+
+import (
+	"fmt"
+	"iter"
+)
+
+// DedupQueue is a FIFO queue that drops a pushed element if an element with the same key
+// was pushed within the last window pushes, whether or not it is still in the queue.
+// Unlike full deduplication, the memory used to remember the keys is bounded by the window.
+// DedupQueue is NOT safe for concurrent use.
+type DedupQueue[T any, K comparable] struct {
+	queue  Queue[T]
+	key    func(T) K
+	window int
+
+	// The keys of the last pushed elements, oldest first.
+	// Invariant: recent.Len() <= window
+	recent Queue[K]
+
+	// The number of occurrences of each key in recent.
+	counts map[K]int
+}
+
+// NewDedupQueue returns an empty queue that suppresses duplicates within the last window pushes.
+// key returns the key used to compare elements; for comparable elements, pass an identity function.
+// If window is not positive, it panics.
+func NewDedupQueue[T any, K comparable](window int, key func(T) K) *DedupQueue[T, K] {
+	if window <= 0 {
+		panic(fmt.Sprintf("queue: window must be positive: %d", window))
+	}
+	return &DedupQueue[T, K]{key: key, window: window, counts: make(map[K]int)}
+}
+
+// Len returns the number of elements in the queue.
+func (q *DedupQueue[T, K]) Len() int {
+	return q.queue.Len()
+}
+
+// IsEmpty returns true if the queue is empty.
+func (q *DedupQueue[T, K]) IsEmpty() bool {
+	return q.queue.IsEmpty()
+}
+
+// AsFIFO returns an adapter of q that implements FIFO.
+// Its Push silently drops duplicates as Push does.
+func (q *DedupQueue[T, K]) AsFIFO() FIFO[T] {
+	return droppingFIFO[T]{q}
+}
+
+// Push adds an element to the back of the queue unless an element with the same key
+// was pushed within the last window pushes. It returns false if the element is dropped.
+// Dropped elements do not count as pushes.
+func (q *DedupQueue[T, K]) Push(x T) bool {
+	k := q.key(x)
+	if q.counts[k] > 0 {
+		return false
+	}
+
+	if q.recent.Len() == q.window {
+		old, _ := q.recent.Pop()
+		if q.counts[old]--; q.counts[old] == 0 {
+			delete(q.counts, old)
+		}
+	}
+	q.recent.Push(k)
+	q.counts[k]++
+	q.queue.Push(x)
+	return true
+}
+
+// Pop removes and returns the element at the front of the queue.
+// Popping does not affect the deduplication window.
+// If the queue is empty, Pop returns the zero value of T and false.
+func (q *DedupQueue[T, K]) Pop() (T, bool) {
+	return q.queue.Pop()
+}
+
+// Peek returns the element at the front of the queue without removing it.
+// If the queue is empty, Peek returns the zero value of T and false.
+func (q *DedupQueue[T, K]) Peek() (T, bool) {
+	return q.queue.Peek()
+}
+
+// All returns an iterator over all elements in the queue.
+// Do not modify the queue while iterating.
+func (q *DedupQueue[T, K]) All() iter.Seq[T] {
+	return q.queue.All()
+}
@@ -0,0 +1,37 @@
+package queue_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestDedupQueue(t *testing.T) {
+	// Setup
+	q := queue.NewDedupQueue(3, func(s string) string { return s })
+
+	// Exercise
+	var dropped []string
+	for _, s := range []string{"a", "b", "a", "c", "b", "d", "a", "d"} {
+		if !q.Push(s) {
+			dropped = append(dropped, s)
+		}
+	}
+
+	// Verify
+	// "a" is accepted again once "b", "c" and "d" have pushed it out of the window.
+	if want := []string{"a", "b", "d"}; !slices.Equal(dropped, want) {
+		t.Errorf("dropped %v; want %v", dropped, want)
+	}
+	if actual, want := slices.Collect(q.All()), []string{"a", "b", "c", "d", "a"}; !slices.Equal(actual, want) {
+		t.Errorf("All() = %v; want %v", actual, want)
+	}
+
+	// Popping does not reopen the window.
+	q.Pop()
+	q.Pop()
+	if q.Push("a") {
+		t.Errorf("Push(a) accepted a duplicate within the window")
+	}
+}
@@ -0,0 +1,40 @@
+package queue
+
+import (
+	"expvar"
+	"sync"
+)
+
+// expvarMetrics is the JSON representation of the metrics published by PublishExpvar.
+type expvarMetrics struct {
+	Len    int    `json:"len"`
+	Cap    int    `json:"cap"`
+	Peak   int    `json:"peak"`
+	Pushes uint64 `json:"pushes"`
+	Pops   uint64 `json:"pops"`
+	Grows  uint64 `json:"grows"`
+}
+
+// PublishExpvar publishes the length, the capacity and the statistics of q
+// as an expvar variable with the given name, so that they appear on /debug/vars.
+// The counters are zero unless EnableStats has been called on q.
+//
+// expvar reads the variable from other goroutines, so mu must be the lock
+// that guards every access to q. Like expvar.Publish, PublishExpvar panics
+// if the name is already registered.
+func PublishExpvar[T any](name string, q *Queue[T], mu sync.Locker) {
+	expvar.Publish(name, expvar.Func(func() any {
+		mu.Lock()
+		defer mu.Unlock()
+
+		stats := q.Stats()
+		return expvarMetrics{
+			Len:    q.Len(),
+			Cap:    q.Cap(),
+			Peak:   stats.Peak,
+			Pushes: stats.Pushes,
+			Pops:   stats.Pops,
+			Grows:  stats.Grows,
+		}
+	}))
+}
@@ -0,0 +1,50 @@
+package queue_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+// expvarRuns makes the published names unique when the test runs multiple times (e.g. -count=2),
+// since expvar does not allow unpublishing.
+var expvarRuns int
+
+func TestPublishExpvar(t *testing.T) {
+	// Setup
+	expvarRuns++
+	name := fmt.Sprintf("TestPublishExpvar%d", expvarRuns)
+	var mu sync.Mutex
+	var q queue.Queue[int]
+	q.EnableStats()
+	queue.PublishExpvar(name, &q, &mu)
+
+	// Exercise
+	mu.Lock()
+	q.PushMany([]int{3, 1, 4, 1, 5})
+	q.Pop()
+	mu.Unlock()
+
+	// Verify
+	var actual map[string]int
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &actual); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int{
+		"len":    4,
+		"cap":    8,
+		"peak":   5,
+		"pushes": 5,
+		"pops":   1,
+		"grows":  1,
+	}
+	for key, want := range expected {
+		if actual[key] != want {
+			t.Errorf("%s: %v; want %v", key, actual[key], want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package queue
+
+// extensions holds the optional state of a queue. It is allocated when any of it is enabled,
+// so that a queue that uses none of it stays small and pays a single nil check per operation.
+type extensions[T any] struct {
+	// The statistics of the queue, or nil if they are not collected.
+	stats *Stats
+
+	// The functions called after the buffer is reallocated, or nil.
+	onGrow   func(oldCap, newCap int)
+	onShrink func(oldCap, newCap int)
+
+	// The allocator of buffers, or nil to allocate them with make.
+	allocator Allocator[T]
+
+	// The watermarks of the length, or nil if they are not set.
+	watermarks *watermarks
+
+	// The peak lengths for adaptive preallocation, or nil if it is disabled.
+	adaptive *adaptiveCapacity
+}
+
+// ensureExtensions returns the optional state of the queue, allocating it if necessary.
+func (q *Queue[T]) ensureExtensions() *extensions[T] {
+	if q.ext == nil {
+		q.ext = &extensions[T]{}
+	}
+	return q.ext
+}
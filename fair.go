@@ -0,0 +1,130 @@
+package queue
+
+import "fmt"
+
+// FairQueue holds a FIFO queue per flow and pops across flows with deficit round robin (DRR),
+// an approximation of weighted fair queuing. The flow of an element is determined by a key function.
+// In each round, every non-empty flow earns quantum × weight credits and may pop elements
+// as long as their costs are covered by its credits, so that each flow gets a share of
+// the total cost proportional to its weight, regardless of how many elements it has queued.
+// FairQueue is NOT safe for concurrent use.
+type FairQueue[K comparable, T any] struct {
+	key     func(T) K
+	cost    func(T) int
+	quantum int
+
+	// The queued elements by flow.
+	flows QueueMap[K, T]
+
+	// The weights set by SetWeight. Flows not in the map have weight 1.
+	weights map[K]int
+
+	// The non-empty flows in round-robin order. The front is the flow being served.
+	active Queue[K]
+
+	// The unused credits of the active flows.
+	deficits map[K]int
+
+	// Whether the front flow of active has already earned its credits in the current round.
+	started bool
+}
+
+// NewFairQueue returns an empty fair queue.
+// key returns the flow of an element, and cost returns its cost, such as its size in bytes;
+// if cost is nil, every element costs 1. quantum is the number of credits a flow of weight 1
+// earns per round. It panics if quantum is not positive.
+func NewFairQueue[K comparable, T any](key func(T) K, cost func(T) int, quantum int) *FairQueue[K, T] {
+	if quantum <= 0 {
+		panic(fmt.Sprintf("queue: quantum must be positive: %d", quantum))
+	}
+	if cost == nil {
+		cost = func(T) int { return 1 }
+	}
+	return &FairQueue[K, T]{
+		key:      key,
+		cost:     cost,
+		quantum:  quantum,
+		weights:  make(map[K]int),
+		deficits: make(map[K]int),
+	}
+}
+
+// Len returns the total number of elements in all flows.
+func (f *FairQueue[K, T]) Len() int {
+	return f.flows.Len()
+}
+
+// IsEmpty returns true if all flows are empty.
+func (f *FairQueue[K, T]) IsEmpty() bool {
+	return f.flows.Len() == 0
+}
+
+// LenOf returns the number of elements in the flow.
+func (f *FairQueue[K, T]) LenOf(flow K) int {
+	return f.flows.LenOf(flow)
+}
+
+// SetWeight sets the weight of the flow. The default weight is 1.
+// It panics if weight is not positive.
+func (f *FairQueue[K, T]) SetWeight(flow K, weight int) {
+	if weight <= 0 {
+		panic(fmt.Sprintf("queue: weight must be positive: %d", weight))
+	}
+	if weight == 1 {
+		delete(f.weights, flow)
+		return
+	}
+	f.weights[flow] = weight
+}
+
+// Push adds an element to the back of its flow.
+func (f *FairQueue[K, T]) Push(x T) {
+	flow := f.key(x)
+	if f.flows.LenOf(flow) == 0 {
+		f.active.Push(flow)
+	}
+	f.flows.PushTo(flow, x)
+}
+
+// Pop removes and returns the next element according to deficit round robin.
+// If all flows are empty, Pop returns the zero value of T and false.
+func (f *FairQueue[K, T]) Pop() (T, bool) {
+	if f.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+
+	// Each iteration either pops an element or ends the turn of a flow after giving it credits,
+	// so the loop terminates once the credits of some flow cover the cost of its front element.
+	for {
+		flow, _ := f.active.Peek()
+		if !f.started {
+			f.deficits[flow] += f.quantum * f.weight(flow)
+			f.started = true
+		}
+
+		x, _ := f.flows.PeekAt(flow)
+		if c := f.cost(x); c <= f.deficits[flow] {
+			f.flows.PopFrom(flow)
+			f.deficits[flow] -= c
+			if f.flows.LenOf(flow) == 0 {
+				// An idle flow must not accumulate credits.
+				delete(f.deficits, flow)
+				f.active.Pop()
+				f.started = false
+			}
+			return x, true
+		}
+
+		f.active.Pop()
+		f.active.Push(flow)
+		f.started = false
+	}
+}
+
+func (f *FairQueue[K, T]) weight(flow K) int {
+	if w, ok := f.weights[flow]; ok {
+		return w
+	}
+	return 1
+}
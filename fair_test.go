@@ -0,0 +1,95 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+type request struct {
+	tenant string
+	size   int
+}
+
+func TestFairQueue_Weights(t *testing.T) {
+	// Setup
+	f := queue.NewFairQueue(func(r request) string { return r.tenant }, nil, 1)
+	f.SetWeight("gold", 3)
+	for range 100 {
+		f.Push(request{tenant: "bronze", size: 1})
+	}
+	for range 100 {
+		f.Push(request{tenant: "gold", size: 1})
+	}
+
+	// Exercise
+	counts := make(map[string]int)
+	for range 40 {
+		r, ok := f.Pop()
+		if !ok {
+			t.Fatalf("Pop() returned false with %v elements", f.Len())
+		}
+		counts[r.tenant]++
+	}
+
+	// Verify
+	if counts["gold"] != 30 || counts["bronze"] != 10 {
+		t.Errorf("served %v; want gold:30 bronze:10", counts)
+	}
+	if f.Len() != 160 || f.LenOf("gold") != 70 {
+		t.Errorf("Len(), LenOf(gold) = %v, %v; want 160, 70", f.Len(), f.LenOf("gold"))
+	}
+}
+
+func TestFairQueue_Costs(t *testing.T) {
+	// Setup
+	f := queue.NewFairQueue(
+		func(r request) string { return r.tenant },
+		func(r request) int { return r.size },
+		100,
+	)
+	// "big" sends few large requests and "small" sends many small ones.
+	for range 10 {
+		f.Push(request{tenant: "big", size: 100})
+	}
+	for range 100 {
+		f.Push(request{tenant: "small", size: 10})
+	}
+
+	// Exercise
+	served := make(map[string]int)
+	for range 55 {
+		r, _ := f.Pop()
+		served[r.tenant] += r.size
+	}
+
+	// Verify
+	// Both tenants get the same amount of cost per round.
+	if served["big"] != 500 || served["small"] != 500 {
+		t.Errorf("served %v; want big:500 small:500", served)
+	}
+}
+
+func TestFairQueue_Drain(t *testing.T) {
+	f := queue.NewFairQueue(func(r request) string { return r.tenant }, func(r request) int { return r.size }, 3)
+	want := map[string]int{"a": 3, "b": 5, "c": 1}
+	for tenant, n := range want {
+		for range n {
+			f.Push(request{tenant: tenant, size: 7})
+		}
+	}
+
+	got := make(map[string]int)
+	for !f.IsEmpty() {
+		r, _ := f.Pop()
+		got[r.tenant]++
+	}
+	for tenant, n := range want {
+		if got[tenant] != n {
+			t.Errorf("popped %v elements of %v; want %v", got[tenant], tenant, n)
+		}
+	}
+	if _, ok := f.Pop(); ok {
+		t.Errorf("Pop() on empty FairQueue returned ok")
+	}
+}
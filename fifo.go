@@ -0,0 +1,67 @@
+package queue
+
+// FIFO is the common interface of the queues in this package, so that application code
+// can be written against it and the implementations can be swapped in tests and benchmarks.
+//
+// Queue, StableQueue, LatencyQueue, IncrementalQueue and ringbuf.RingBuffer implement FIFO,
+// and so does PriorityQueue, whose Pop returns elements in priority order instead.
+// Queues whose Push can reject an element, such as SyncQueue, BudgetQueue and DedupQueue,
+// report the rejection from Push, so they provide AsFIFO, which adapts them to FIFO.
+type FIFO[T any] interface {
+	// Push adds an element to the queue.
+	Push(x T)
+
+	// Pop removes and returns the next element.
+	// If the queue is empty, Pop returns the zero value of T and false.
+	Pop() (T, bool)
+
+	// Peek returns the next element without removing it.
+	// If the queue is empty, Peek returns the zero value of T and false.
+	Peek() (T, bool)
+
+	// Len returns the number of elements in the queue.
+	Len() int
+}
+
+// syncFIFO adapts a SyncQueue to FIFO.
+type syncFIFO[T any] struct {
+	q *SyncQueue[T]
+}
+
+// Push adds an element to the back of the queue.
+// It panics if the queue has been closed, like a send on a closed channel.
+func (f syncFIFO[T]) Push(x T) {
+	if err := f.q.Push(x); err != nil {
+		panic("queue: push to closed SyncQueue")
+	}
+}
+
+func (f syncFIFO[T]) Pop() (T, bool) {
+	return f.q.Pop()
+}
+
+func (f syncFIFO[T]) Peek() (T, bool) {
+	return f.q.Peek()
+}
+
+func (f syncFIFO[T]) Len() int {
+	return f.q.Len()
+}
+
+// droppingQueue is a queue whose Push reports whether the element was accepted.
+type droppingQueue[T any] interface {
+	Push(x T) bool
+	Pop() (T, bool)
+	Peek() (T, bool)
+	Len() int
+}
+
+// droppingFIFO adapts a droppingQueue to FIFO by discarding the result of Push.
+type droppingFIFO[T any] struct {
+	droppingQueue[T]
+}
+
+// Push adds an element to the queue, or drops it if the queue rejects it.
+func (f droppingFIFO[T]) Push(x T) {
+	f.droppingQueue.Push(x)
+}
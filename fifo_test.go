@@ -0,0 +1,100 @@
+package queue_test
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/nojima/queue-go"
+	"github.com/nojima/queue-go/queuetest"
+	"github.com/nojima/queue-go/ringbuf"
+)
+
+var (
+	_ queue.FIFO[int] = (*queue.Queue[int])(nil)
+	_ queue.FIFO[int] = (*queue.StableQueue[int])(nil)
+	_ queue.FIFO[int] = (*queue.LatencyQueue[int])(nil)
+	_ queue.FIFO[int] = (*queue.IncrementalQueue[int])(nil)
+	_ queue.FIFO[int] = (*ringbuf.RingBuffer[int])(nil)
+	_ queue.FIFO[int] = (&queue.SyncQueue[int]{}).AsFIFO()
+	_ queue.FIFO[int] = queue.NewBudgetQueue(10, func(int) int { return 1 }).AsFIFO()
+	_ queue.FIFO[int] = queue.NewDedupQueue(10, func(x int) int { return x }).AsFIFO()
+
+	// PriorityQueue does not pop in FIFO order, so it is only checked to implement the interface.
+	_ queue.FIFO[int] = queue.NewPriorityQueue(cmp.Compare[int])
+)
+
+func TestFIFO_implementations(t *testing.T) {
+	testCases := []struct {
+		title    string
+		newQueue func() queue.FIFO[int]
+	}{
+		{
+			title:    "Queue",
+			newQueue: func() queue.FIFO[int] { return &queue.Queue[int]{} },
+		},
+		{
+			title:    "StableQueue",
+			newQueue: func() queue.FIFO[int] { return &queue.StableQueue[int]{} },
+		},
+		{
+			title:    "LatencyQueue",
+			newQueue: func() queue.FIFO[int] { return &queue.LatencyQueue[int]{} },
+		},
+		{
+			title:    "IncrementalQueue",
+			newQueue: func() queue.FIFO[int] { return &queue.IncrementalQueue[int]{} },
+		},
+		{
+			title:    "RingBuffer",
+			newQueue: func() queue.FIFO[int] { return &ringbuf.RingBuffer[int]{} },
+		},
+		{
+			title:    "SyncQueue",
+			newQueue: func() queue.FIFO[int] { return (&queue.SyncQueue[int]{}).AsFIFO() },
+		},
+		{
+			// The budget is large enough that no element is dropped.
+			title: "BudgetQueue",
+			newQueue: func() queue.FIFO[int] {
+				return queue.NewBudgetQueue(math.MaxInt, func(int) int { return 1 }).AsFIFO()
+			},
+		},
+		{
+			// The elements are random 63-bit integers, so no element is dropped as a duplicate.
+			title: "DedupQueue",
+			newQueue: func() queue.FIFO[int] {
+				return queue.NewDedupQueue(1000, func(x int) int { return x }).AsFIFO()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			queuetest.Run(t, 100, tc.newQueue, (*rand.Rand).Int)
+		})
+	}
+}
+
+func TestFIFO_adapterPanicsWhenClosed(t *testing.T) {
+	var q queue.SyncQueue[int]
+	f := q.AsFIFO()
+	q.Close()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Push() to a closed SyncQueue did not panic")
+		}
+	}()
+	f.Push(1)
+}
+
+func TestFIFO_adapterDrops(t *testing.T) {
+	f := queue.NewBudgetQueue(2, func(int) int { return 1 }).AsFIFO()
+	f.Push(1)
+	f.Push(2)
+	f.Push(3)
+	if f.Len() != 2 {
+		t.Errorf("Len() = %v; want 2", f.Len())
+	}
+}
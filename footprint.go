@@ -0,0 +1,25 @@
+package queue
+
+import "unsafe"
+
+// CapBytes returns the size of the buffer in bytes, that is, Cap() times the size of T.
+// For element types that do not reference other memory, this is the whole footprint of the queue.
+func (q *Queue[T]) CapBytes() int {
+	var zero T
+	return len(q.buffer) * int(unsafe.Sizeof(zero))
+}
+
+// MemoryFootprint estimates the memory used by the queue in bytes.
+// It is the sum of CapBytes and size(x) for each element x, where size should return
+// the number of bytes referenced by x outside the buffer (e.g. the capacity of a slice).
+// If size is nil, MemoryFootprint is the same as CapBytes.
+func (q *Queue[T]) MemoryFootprint(size func(T) int) int {
+	n := q.CapBytes()
+	if size == nil {
+		return n
+	}
+	for i := range q.length {
+		n += size(q.buffer[q.wrap(q.head+i)])
+	}
+	return n
+}
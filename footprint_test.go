@@ -0,0 +1,33 @@
+package queue_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestQueue_CapBytes(t *testing.T) {
+	var q queue.Queue[int64]
+	q.PushMany([]int64{3, 1, 4})
+
+	if actual := q.CapBytes(); actual != 4*8 {
+		t.Errorf("CapBytes() = %v; want %v", actual, 4*8)
+	}
+}
+
+func TestQueue_MemoryFootprint(t *testing.T) {
+	var q queue.Queue[[]byte]
+	q.Push(make([]byte, 10))
+	q.Push(make([]byte, 20, 30))
+
+	sliceSize := int(unsafe.Sizeof([]byte(nil)))
+	actual := q.MemoryFootprint(func(b []byte) int { return cap(b) })
+	expected := 2*sliceSize + 10 + 30
+	if actual != expected {
+		t.Errorf("MemoryFootprint() = %v; want %v", actual, expected)
+	}
+	if actual := q.MemoryFootprint(nil); actual != 2*sliceSize {
+		t.Errorf("MemoryFootprint(nil) = %v; want %v", actual, 2*sliceSize)
+	}
+}
@@ -0,0 +1,43 @@
+package queue
+
+// OnGrow registers f to be called after the buffer is reallocated to grow,
+// with the capacities before and after the reallocation.
+// It replaces the function registered before. Passing nil removes it.
+func (q *Queue[T]) OnGrow(f func(oldCap, newCap int)) {
+	if f == nil && q.ext == nil {
+		return
+	}
+	q.ensureExtensions().onGrow = f
+}
+
+// OnShrink registers f to be called after the buffer is reallocated to shrink,
+// with the capacities before and after the reallocation.
+// It replaces the function registered before. Passing nil removes it.
+func (q *Queue[T]) OnShrink(f func(oldCap, newCap int)) {
+	if f == nil && q.ext == nil {
+		return
+	}
+	q.ensureExtensions().onShrink = f
+}
+
+// beginCallback releases the guard of the operation in progress before a user callback
+// is called, and returns the state that endCallback needs.
+func (q *Queue[T]) beginCallback() (*string, uint) {
+	if raceGuard {
+		return q.guard.suspend(), q.version
+	}
+	return nil, q.version
+}
+
+// endCallback reacquires the guard after the user callback named name has returned.
+// With the queuerace build tag, it panics if the callback modified the queue,
+// which is not allowed because the operation that called it has not finished yet.
+func (q *Queue[T]) endCallback(name string, op *string, version uint) {
+	if raceGuard {
+		q.guard.resume(op)
+		if q.version != version {
+			panic("queue: the queue was modified by the " + name + " callback " +
+				"in the middle of an operation")
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package queue_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestQueue_OnGrow(t *testing.T) {
+	// Setup
+	var q queue.Queue[int]
+	var actual [][2]int
+	q.OnGrow(func(oldCap, newCap int) {
+		actual = append(actual, [2]int{oldCap, newCap})
+	})
+
+	// Exercise
+	for i := range 5 {
+		q.Push(i)
+	}
+	q.PushMany([]int{5, 6, 7, 8, 9, 10, 11, 12})
+
+	// Verify
+	expected := [][2]int{{0, 1}, {1, 2}, {2, 4}, {4, 8}, {8, 16}}
+	if !slices.Equal(actual, expected) {
+		t.Errorf("actual: %v; want: %v", actual, expected)
+	}
+}
+
+func TestQueue_OnShrink(t *testing.T) {
+	// Setup
+	q := newQueue(5, 3, 1, 4, 1, 5, 9, 2)
+	q.Truncate(3)
+	var actual [][2]int
+	q.OnShrink(func(oldCap, newCap int) {
+		actual = append(actual, [2]int{oldCap, newCap})
+	})
+
+	// Exercise
+	q.ShrinkToFit()
+	q.ShrinkToFit()
+
+	// Verify
+	expected := [][2]int{{8, 4}}
+	if !slices.Equal(actual, expected) {
+		t.Errorf("actual: %v; want: %v", actual, expected)
+	}
+	if elements := slices.Collect(q.All()); !slices.Equal(elements, []int{3, 1, 4}) {
+		t.Errorf("elements: %v; want: %v", elements, []int{3, 1, 4})
+	}
+	if q.Cap() != 4 {
+		t.Errorf("Cap() = %v; want %v", q.Cap(), 4)
+	}
+}
@@ -0,0 +1,136 @@
+package queue
+
+import "github.com/nojima/queue-go/ringbuf"
+
+// incrementalStep is the number of elements IncrementalQueue migrates per operation.
+// It must be at least 2 so that a migration finishes before the new buffer fills up.
+const incrementalStep = 2
+
+// IncrementalQueue is a FIFO queue that grows its buffer incrementally.
+//
+// When Queue runs out of capacity, a single Push copies all elements to a new buffer,
+// which takes O(n) time. IncrementalQueue instead keeps the old buffer and migrates
+// a few elements per Push and Pop, so that no operation copies more than a few elements.
+// The Push that grows the buffer still allocates a buffer of 2n elements, which the runtime
+// zeroes in O(n) time; that is much cheaper than copying the elements, but it is not O(1).
+// This suits soft real-time workloads with large queues, at the cost of holding both
+// buffers during a migration and slightly slower operations.
+//
+// The zero value for IncrementalQueue is an empty queue ready to use.
+// IncrementalQueue is NOT safe for concurrent use.
+type IncrementalQueue[T any] struct {
+	// The elements. During a migration, the slots of the elements that have not been
+	// migrated yet hold zero values.
+	ring ringbuf.RingBuffer[T]
+
+	// The previous buffer whose elements are being migrated to ring, or nil.
+	// It holds the elements not migrated yet at the same positions as in ring.
+	// Invariant: ring.Head() <= old.Head() and old.Tail() <= ring.Tail()  (old != nil)
+	old *ringbuf.RingBuffer[T]
+}
+
+// Len returns the number of elements in the queue.
+func (q *IncrementalQueue[T]) Len() int {
+	return q.ring.Len()
+}
+
+// Cap returns the number of elements the queue can hold without allocating a new buffer.
+func (q *IncrementalQueue[T]) Cap() int {
+	return q.ring.Cap()
+}
+
+// IsEmpty returns true if the queue is empty.
+func (q *IncrementalQueue[T]) IsEmpty() bool {
+	return q.ring.Len() == 0
+}
+
+// IsMigrating returns true if elements are still being migrated from the previous buffer.
+func (q *IncrementalQueue[T]) IsMigrating() bool {
+	return q.old != nil
+}
+
+// Push adds an element to the back of the queue.
+func (q *IncrementalQueue[T]) Push(x T) {
+	q.migrate(incrementalStep)
+	if q.ring.Free() == 0 {
+		q.grow()
+	}
+	q.ring.Push(x)
+}
+
+// Pop removes and returns the element at the front of the queue.
+// If the queue is empty, Pop returns the zero value of T and false.
+func (q *IncrementalQueue[T]) Pop() (T, bool) {
+	q.migrate(incrementalStep)
+	if q.old == nil || q.old.Head() != q.ring.Head() {
+		return q.ring.Pop()
+	}
+
+	// The first element has not been migrated yet, so take it from the old buffer
+	// and discard the placeholder.
+	x, _ := q.old.Pop()
+	q.ring.AdvanceHead(1)
+	if q.old.Len() == 0 {
+		q.old = nil
+	}
+	return x, true
+}
+
+// Peek returns the element at the front of the queue without removing it.
+// If the queue is empty, Peek returns the zero value of T and false.
+func (q *IncrementalQueue[T]) Peek() (T, bool) {
+	if q.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+	return q.At(0), true
+}
+
+// At returns the i-th element from the front of the queue.
+// It panics if i is out of range.
+func (q *IncrementalQueue[T]) At(i int) T {
+	if i < 0 || i >= q.ring.Len() {
+		panic("queue: index out of range")
+	}
+	if q.old != nil {
+		if j := q.ring.Head() + uint(i) - q.old.Head(); j < uint(q.old.Len()) {
+			return q.old.At(int(j))
+		}
+	}
+	return q.ring.At(i)
+}
+
+// grow allocates a buffer twice as large and starts migrating the elements to it.
+// Caller must guarantee that the buffer is full.
+func (q *IncrementalQueue[T]) grow() {
+	// A migration finishes within len(old)/incrementalStep operations,
+	// so it is only pending here if incrementalStep has been set too small.
+	q.migrate(q.ring.Len())
+	if q.ring.Cap() == 0 {
+		q.ring.Reserve(1)
+		return
+	}
+
+	old := q.ring
+	q.old = &old
+	q.ring = ringbuf.RingBuffer[T]{}
+	q.ring.Reserve(2 * old.Cap())
+	// Keep the positions so that the elements map to distinct slots of the new buffer,
+	// which is twice as large, and reserve the slots for the elements to be migrated.
+	q.ring.ResetAt(old.Head())
+	q.ring.AdvanceTail(old.Len())
+}
+
+// migrate moves up to n elements from the previous buffer to the current one.
+func (q *IncrementalQueue[T]) migrate(n int) {
+	if q.old == nil {
+		return
+	}
+	for ; n > 0 && q.old.Len() > 0; n-- {
+		x, _ := q.old.Pop()
+		q.ring.Set(int(q.old.Head()-1-q.ring.Head()), x)
+	}
+	if q.old.Len() == 0 {
+		q.old = nil
+	}
+}
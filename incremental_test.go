@@ -0,0 +1,74 @@
+package queue_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestIncrementalQueue_migration(t *testing.T) {
+	// Setup: fill the buffer so that the next Push starts a migration.
+	var q queue.IncrementalQueue[int]
+	for i := range 8 {
+		q.Push(i)
+	}
+	q.Pop()
+	q.Push(8)
+	if q.IsMigrating() {
+		t.Fatalf("IsMigrating() = true before the buffer is full")
+	}
+
+	// Exercise
+	q.Push(9)
+
+	// Verify
+	if !q.IsMigrating() || q.Cap() != 16 {
+		t.Errorf("IsMigrating(), Cap() = %v, %v; want true, 16", q.IsMigrating(), q.Cap())
+	}
+	for i := range q.Len() {
+		if x := q.At(i); x != i+1 {
+			t.Errorf("At(%v) = %v; want %v", i, x, i+1)
+		}
+	}
+	for i := 1; i <= 9; i++ {
+		if x, ok := q.Pop(); x != i || !ok {
+			t.Errorf("Pop() = %v, %v; want %v, true", x, ok, i)
+		}
+	}
+	if q.IsMigrating() {
+		t.Errorf("IsMigrating() = true after the migration should have finished")
+	}
+}
+
+func TestIncrementalQueue_random(t *testing.T) {
+	// Setup
+	rng := rand.New(rand.NewSource(1))
+	var q queue.IncrementalQueue[int]
+	var expected queue.Queue[int]
+
+	for step := range 10000 {
+		// Exercise: push more often than pop so that the queue keeps growing.
+		if rng.Intn(3) != 0 {
+			q.Push(step)
+			expected.Push(step)
+		} else {
+			x, ok := q.Pop()
+			y, expectedOK := expected.Pop()
+			if x != y || ok != expectedOK {
+				t.Fatalf("step %v: Pop() = %v, %v; want %v, %v", step, x, ok, y, expectedOK)
+			}
+		}
+
+		// Verify
+		if q.Len() != expected.Len() {
+			t.Fatalf("step %v: Len() = %v; want %v", step, q.Len(), expected.Len())
+		}
+		if q.Len() > 0 {
+			i := rng.Intn(q.Len())
+			if q.At(i) != expected.At(i) {
+				t.Fatalf("step %v: At(%v) = %v; want %v", step, i, q.At(i), expected.At(i))
+			}
+		}
+	}
+}
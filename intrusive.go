@@ -0,0 +1,94 @@
+package queue
+
+// Node is embedded in an element type to make it linkable into an Intrusive queue.
+// It must not be modified or copied while the element is in a queue.
+type Node[T any] struct {
+	next   *T
+	queued bool
+}
+
+func (n *Node[T]) queueNode() *Node[T] {
+	return n
+}
+
+// Intrusive is a FIFO queue that links elements through a Node embedded in them
+// instead of storing them in a buffer. Push neither copies the element nor allocates,
+// which suits pooled objects such as requests in a high-throughput server.
+// P is the pointer type to T, for example:
+//
+//	type Request struct {
+//		queue.Node[Request]
+//		...
+//	}
+//
+//	var q queue.Intrusive[Request, *Request]
+//
+// An element can be in at most one Intrusive queue at a time.
+// The zero value for Intrusive is an empty queue ready to use.
+// Intrusive is NOT safe for concurrent use.
+type Intrusive[T any, P interface {
+	*T
+	queueNode() *Node[T]
+}] struct {
+	// The first and last elements, or nil if the queue is empty.
+	head, tail *T
+
+	// The number of elements in the queue.
+	length int
+}
+
+// Len returns the number of elements in the queue.
+func (q *Intrusive[T, P]) Len() int {
+	return q.length
+}
+
+// IsEmpty returns true if the queue is empty.
+func (q *Intrusive[T, P]) IsEmpty() bool {
+	return q.length == 0
+}
+
+// Push adds an element to the back of the queue.
+// It panics if the element is already in a queue.
+func (q *Intrusive[T, P]) Push(x P) {
+	n := x.queueNode()
+	if n.queued {
+		panic("queue: element is already in a queue")
+	}
+	n.queued = true
+
+	if q.tail == nil {
+		q.head = x
+	} else {
+		P(q.tail).queueNode().next = x
+	}
+	q.tail = x
+	q.length++
+}
+
+// Pop removes and returns the element at the front of the queue.
+// If the queue is empty, Pop returns nil and false.
+func (q *Intrusive[T, P]) Pop() (P, bool) {
+	if q.head == nil {
+		return nil, false
+	}
+
+	x := P(q.head)
+	n := x.queueNode()
+	q.head = n.next
+	if q.head == nil {
+		q.tail = nil
+	}
+	n.next = nil
+	n.queued = false
+	q.length--
+	return x, true
+}
+
+// Peek returns the element at the front of the queue without removing it.
+// If the queue is empty, Peek returns nil and false.
+func (q *Intrusive[T, P]) Peek() (P, bool) {
+	if q.head == nil {
+		return nil, false
+	}
+	return P(q.head), true
+}
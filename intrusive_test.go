@@ -0,0 +1,69 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+type job struct {
+	queue.Node[job]
+	id int
+}
+
+func TestIntrusive(t *testing.T) {
+	// Setup
+	var q queue.Intrusive[job, *job]
+	jobs := []*job{{id: 1}, {id: 2}, {id: 3}}
+
+	// Exercise
+	for _, j := range jobs {
+		q.Push(j)
+	}
+
+	// Verify
+	if q.Len() != 3 {
+		t.Errorf("Len() = %v; want 3", q.Len())
+	}
+	if j, ok := q.Peek(); !ok || j != jobs[0] {
+		t.Errorf("Peek() = (%v, %v); want the first job", j, ok)
+	}
+	for _, want := range jobs {
+		if j, ok := q.Pop(); !ok || j != want {
+			t.Errorf("Pop() = (%v, %v); want job %v", j, ok, want.id)
+		}
+	}
+	if j, ok := q.Pop(); ok || j != nil {
+		t.Errorf("Pop() on empty queue = (%v, %v); want (nil, false)", j, ok)
+	}
+
+	// A popped element can be pushed again.
+	q.Push(jobs[1])
+	if j, _ := q.Pop(); j != jobs[1] {
+		t.Errorf("Pop() = %v; want job 2", j)
+	}
+}
+
+func TestIntrusive_PushTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Push() of a queued element did not panic")
+		}
+	}()
+	var q queue.Intrusive[job, *job]
+	j := &job{id: 1}
+	q.Push(j)
+	q.Push(j)
+}
+
+func TestIntrusive_ZeroAllocation(t *testing.T) {
+	var q queue.Intrusive[job, *job]
+	j := &job{id: 1}
+	allocs := testing.AllocsPerRun(100, func() {
+		q.Push(j)
+		q.Pop()
+	})
+	if allocs != 0 {
+		t.Errorf("Push and Pop allocated %v times; want 0", allocs)
+	}
+}
@@ -0,0 +1,87 @@
+package queue
+
+import "fmt"
+
+// Lanes is a set of FIFO queues ("lanes") with strict priorities.
+// Lane 0 has the highest priority; Pop always serves a lane only when all lanes
+// with smaller numbers are empty. Lanes are created on demand by Push.
+// It is a lightweight alternative to a heap when there are only a few priority levels,
+// such as "urgent" and "normal".
+// The zero value for Lanes is an empty set of lanes ready to use.
+// Lanes is NOT safe for concurrent use.
+type Lanes[T any] struct {
+	lanes []Queue[T]
+
+	// The total number of elements in all lanes.
+	length int
+}
+
+// Len returns the total number of elements in all lanes.
+func (l *Lanes[T]) Len() int {
+	return l.length
+}
+
+// IsEmpty returns true if all lanes are empty.
+func (l *Lanes[T]) IsEmpty() bool {
+	return l.length == 0
+}
+
+// LenOf returns the number of elements in the given lane.
+func (l *Lanes[T]) LenOf(lane int) int {
+	if lane < 0 || lane >= len(l.lanes) {
+		return 0
+	}
+	return l.lanes[lane].Len()
+}
+
+// Push adds an element to the back of the given lane.
+// It panics if lane is negative.
+func (l *Lanes[T]) Push(lane int, x T) {
+	if lane < 0 {
+		panic(fmt.Sprintf("queue: negative lane: %d", lane))
+	}
+	for len(l.lanes) <= lane {
+		l.lanes = append(l.lanes, Queue[T]{})
+	}
+	l.lanes[lane].Push(x)
+	l.length++
+}
+
+// Pop removes and returns the element at the front of the highest-priority non-empty lane,
+// together with the lane number.
+// If all lanes are empty, Pop returns the zero value of T, -1 and false.
+func (l *Lanes[T]) Pop() (T, int, bool) {
+	lane := l.first()
+	if lane < 0 {
+		var zero T
+		return zero, -1, false
+	}
+	x, _ := l.lanes[lane].Pop()
+	l.length--
+	return x, lane, true
+}
+
+// Peek returns the element that Pop would return next, without removing it.
+// If all lanes are empty, Peek returns the zero value of T, -1 and false.
+func (l *Lanes[T]) Peek() (T, int, bool) {
+	lane := l.first()
+	if lane < 0 {
+		var zero T
+		return zero, -1, false
+	}
+	x, _ := l.lanes[lane].Peek()
+	return x, lane, true
+}
+
+// first returns the number of the highest-priority non-empty lane, or -1 if all lanes are empty.
+func (l *Lanes[T]) first() int {
+	if l.IsEmpty() {
+		return -1
+	}
+	for i := range l.lanes {
+		if !l.lanes[i].IsEmpty() {
+			return i
+		}
+	}
+	panic("queue: Lanes is inconsistent: all lanes are empty while length != 0")
+}
@@ -0,0 +1,80 @@
+package queue_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func ExampleLanes() {
+	const (
+		urgent = 0
+		normal = 1
+	)
+	var l queue.Lanes[string]
+	l.Push(normal, "a")
+	l.Push(normal, "b")
+	l.Push(urgent, "X")
+	l.Push(normal, "c")
+	l.Push(urgent, "Y")
+
+	for !l.IsEmpty() {
+		x, lane, _ := l.Pop()
+		fmt.Println(lane, x)
+	}
+	// Output:
+	// 0 X
+	// 0 Y
+	// 1 a
+	// 1 b
+	// 1 c
+}
+
+func TestLanes(t *testing.T) {
+	var l queue.Lanes[int]
+	if _, lane, ok := l.Pop(); ok || lane != -1 {
+		t.Errorf("Pop() on empty Lanes = (_, %v, %v); want (_, -1, false)", lane, ok)
+	}
+
+	l.Push(2, 20)
+	l.Push(0, 1)
+	l.Push(2, 21)
+	if l.Len() != 3 || l.LenOf(2) != 2 || l.LenOf(1) != 0 || l.LenOf(5) != 0 {
+		t.Errorf("Len() = %v, LenOf(2) = %v; want 3, 2", l.Len(), l.LenOf(2))
+	}
+	if x, lane, ok := l.Peek(); !ok || x != 1 || lane != 0 {
+		t.Errorf("Peek() = (%v, %v, %v); want (1, 0, true)", x, lane, ok)
+	}
+
+	var got []int
+	for {
+		x, _, ok := l.Pop()
+		if !ok {
+			break
+		}
+		got = append(got, x)
+		if x == 20 {
+			// A higher-priority element pushed in the middle is served next.
+			l.Push(1, 10)
+		}
+	}
+	want := []int{1, 20, 10, 21}
+	if !slices.Equal(got, want) {
+		t.Errorf("popped %v; want %v", got, want)
+	}
+	if l.Len() != 0 {
+		t.Errorf("Len() = %v; want 0", l.Len())
+	}
+}
+
+func TestLanes_PushNegativeLane(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Push() with a negative lane did not panic")
+		}
+	}()
+	var l queue.Lanes[int]
+	l.Push(-1, 0)
+}
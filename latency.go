@@ -0,0 +1,128 @@
+package queue
+
+import (
+	"math/bits"
+	"time"
+)
+
+// LatencyQueue is a FIFO queue that records how long each element waits in the queue,
+// from Push to Pop, in a small histogram.
+// The zero value for LatencyQueue is an empty queue ready to use.
+// LatencyQueue is NOT safe for concurrent use.
+type LatencyQueue[T any] struct {
+	queue Queue[timedElement[T]]
+
+	// The number of popped elements by the bit length of their wait time in nanoseconds,
+	// so bucket i counts the wait times in [2^(i-1), 2^i) nanoseconds.
+	buckets [65]uint64
+	count   uint64
+	sum     time.Duration
+	max     time.Duration
+}
+
+type timedElement[T any] struct {
+	value    T
+	pushedAt time.Time
+}
+
+// LatencyStats is a summary of the wait times of the popped elements.
+// The percentiles are approximate: they are the upper bounds of the histogram buckets
+// containing them, which are within a factor of two of the exact values, capped at Max.
+type LatencyStats struct {
+	Count uint64
+	Mean  time.Duration
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+// Len returns the number of elements in the queue.
+func (q *LatencyQueue[T]) Len() int {
+	return q.queue.Len()
+}
+
+// IsEmpty returns true if the queue is empty.
+func (q *LatencyQueue[T]) IsEmpty() bool {
+	return q.queue.IsEmpty()
+}
+
+// Push adds an element to the back of the queue and records the time.
+func (q *LatencyQueue[T]) Push(x T) {
+	q.queue.Push(timedElement[T]{value: x, pushedAt: time.Now()})
+}
+
+// Pop removes and returns the element at the front of the queue and records its wait time.
+// If the queue is empty, Pop returns the zero value of T and false.
+func (q *LatencyQueue[T]) Pop() (T, bool) {
+	e, ok := q.queue.Pop()
+	if ok {
+		q.record(time.Since(e.pushedAt))
+	}
+	return e.value, ok
+}
+
+// Peek returns the element at the front of the queue without removing it.
+// If the queue is empty, Peek returns the zero value of T and false.
+func (q *LatencyQueue[T]) Peek() (T, bool) {
+	e, ok := q.queue.Peek()
+	return e.value, ok
+}
+
+// OldestAge returns how long the element at the front of the queue has been waiting,
+// or zero if the queue is empty.
+func (q *LatencyQueue[T]) OldestAge() time.Duration {
+	e, ok := q.queue.Peek()
+	if !ok {
+		return 0
+	}
+	return time.Since(e.pushedAt)
+}
+
+// Latency returns the summary of the wait times recorded since the queue was created
+// or ResetLatency was called.
+func (q *LatencyQueue[T]) Latency() LatencyStats {
+	if q.count == 0 {
+		return LatencyStats{}
+	}
+	return LatencyStats{
+		Count: q.count,
+		Mean:  q.sum / time.Duration(q.count),
+		P50:   q.percentile(0.50),
+		P95:   q.percentile(0.95),
+		P99:   q.percentile(0.99),
+		Max:   q.max,
+	}
+}
+
+// ResetLatency discards the recorded wait times.
+func (q *LatencyQueue[T]) ResetLatency() {
+	q.buckets = [65]uint64{}
+	q.count = 0
+	q.sum = 0
+	q.max = 0
+}
+
+func (q *LatencyQueue[T]) record(d time.Duration) {
+	d = max(d, 0)
+	q.buckets[bits.Len64(uint64(d))]++
+	q.count++
+	q.sum += d
+	q.max = max(q.max, d)
+}
+
+// percentile returns the upper bound of the bucket containing the p-quantile, capped at max.
+func (q *LatencyQueue[T]) percentile(p float64) time.Duration {
+	rank := uint64(p*float64(q.count-1)) + 1
+	var seen uint64
+	for i, n := range q.buckets {
+		seen += n
+		if seen >= rank {
+			if i == 0 {
+				return 0
+			}
+			return min(time.Duration(uint64(1)<<i-1), q.max)
+		}
+	}
+	return q.max
+}
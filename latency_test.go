@@ -0,0 +1,50 @@
+package queue_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestLatencyQueue(t *testing.T) {
+	// Setup
+	var q queue.LatencyQueue[int]
+	if s := q.Latency(); s != (queue.LatencyStats{}) {
+		t.Errorf("Latency() = %+v before any Pop; want zero", s)
+	}
+
+	// Exercise
+	for i := range 10 {
+		q.Push(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if age := q.OldestAge(); age < 20*time.Millisecond {
+		t.Errorf("OldestAge() = %v; want at least 20ms", age)
+	}
+	for i := range 10 {
+		if x, ok := q.Pop(); !ok || x != i {
+			t.Fatalf("Pop() = (%v, %v); want (%v, true)", x, ok, i)
+		}
+	}
+
+	// Verify
+	s := q.Latency()
+	if s.Count != 10 {
+		t.Errorf("Count = %v; want 10", s.Count)
+	}
+	if s.Max < 20*time.Millisecond || s.Mean < 20*time.Millisecond {
+		t.Errorf("Max = %v, Mean = %v; want at least 20ms", s.Max, s.Mean)
+	}
+	// The percentiles are within a factor of two and not greater than Max.
+	for _, p := range []time.Duration{s.P50, s.P95, s.P99} {
+		if p < 10*time.Millisecond || p > s.Max {
+			t.Errorf("percentile = %v; want in [10ms, %v]", p, s.Max)
+		}
+	}
+
+	q.ResetLatency()
+	if s := q.Latency(); s.Count != 0 {
+		t.Errorf("Count = %v after ResetLatency; want 0", s.Count)
+	}
+}
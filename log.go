@@ -0,0 +1,96 @@
+package queue
+
+import "fmt"
+
+// Log is an append-only queue whose elements are read through cursors and
+// stay readable until they are explicitly released with Trim.
+// Each element is identified by its offset, which starts from zero and increases by one
+// for each appended element. Consumers can seek their cursors back and replay elements
+// that have not been trimmed yet, which enables at-least-once processing.
+// The zero value for Log is an empty log ready to use.
+// Log is NOT safe for concurrent use.
+type Log[T any] struct {
+	queue Queue[T]
+
+	// The offset of the first element in queue.
+	start uint64
+}
+
+// LogCursor is a read position in a Log.
+type LogCursor[T any] struct {
+	log    *Log[T]
+	offset uint64
+}
+
+// Start returns the offset of the first element that has not been trimmed.
+func (l *Log[T]) Start() uint64 {
+	return l.start
+}
+
+// End returns the offset that will be assigned to the next appended element.
+func (l *Log[T]) End() uint64 {
+	return l.start + uint64(l.queue.Len())
+}
+
+// Len returns the number of elements retained in the log.
+func (l *Log[T]) Len() int {
+	return l.queue.Len()
+}
+
+// Append adds an element to the end of the log and returns its offset.
+func (l *Log[T]) Append(x T) uint64 {
+	offset := l.End()
+	l.queue.Push(x)
+	return offset
+}
+
+// Get returns the element at the offset.
+// If the offset has been trimmed or not been appended yet, Get returns the zero value of T and false.
+func (l *Log[T]) Get(offset uint64) (T, bool) {
+	if offset < l.start || offset >= l.End() {
+		var zero T
+		return zero, false
+	}
+	return l.queue.At(int(offset - l.start)), true
+}
+
+// Trim releases the elements whose offsets are less than upTo.
+// Trimming offsets that have already been trimmed has no effect.
+// If upTo is greater than End(), it panics.
+func (l *Log[T]) Trim(upTo uint64) {
+	if upTo > l.End() {
+		panic(fmt.Sprintf("queue: trim offset out of range: upTo=%d, end=%d", upTo, l.End()))
+	}
+	if upTo <= l.start {
+		return
+	}
+	l.queue.consume(int(upTo - l.start))
+	l.start = upTo
+}
+
+// Cursor returns a new cursor positioned at the offset.
+func (l *Log[T]) Cursor(offset uint64) *LogCursor[T] {
+	return &LogCursor[T]{log: l, offset: offset}
+}
+
+// Offset returns the offset of the element that Next will return.
+func (c *LogCursor[T]) Offset() uint64 {
+	return c.offset
+}
+
+// Seek moves the cursor to the offset.
+func (c *LogCursor[T]) Seek(offset uint64) {
+	c.offset = offset
+}
+
+// Next returns the element at the cursor and advances the cursor.
+// If the cursor points to an element that has been trimmed, Next skips to Start() first.
+// If there is no element to read, Next returns the zero value of T and false.
+func (c *LogCursor[T]) Next() (T, bool) {
+	c.offset = max(c.offset, c.log.start)
+	x, ok := c.log.Get(c.offset)
+	if ok {
+		c.offset++
+	}
+	return x, ok
+}
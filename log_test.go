@@ -0,0 +1,56 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestLog(t *testing.T) {
+	var l queue.Log[string]
+	for i, s := range []string{"a", "b", "c", "d"} {
+		if offset := l.Append(s); offset != uint64(i) {
+			t.Errorf("Append(%q) = %v; want %v", s, offset, i)
+		}
+	}
+
+	readAll := func(c *queue.LogCursor[string]) string {
+		var s string
+		for {
+			x, ok := c.Next()
+			if !ok {
+				return s
+			}
+			s += x
+		}
+	}
+
+	c := l.Cursor(l.Start())
+	if s := readAll(c); s != "abcd" {
+		t.Errorf("read %q; want %q", s, "abcd")
+	}
+
+	// Processing failed after "b": replay from there.
+	c.Seek(2)
+	if s := readAll(c); s != "cd" {
+		t.Errorf("replayed %q; want %q", s, "cd")
+	}
+
+	l.Trim(3)
+	if l.Start() != 3 || l.End() != 4 || l.Len() != 1 {
+		t.Errorf("Start() = %v, End() = %v, Len() = %v; want 3, 4, 1", l.Start(), l.End(), l.Len())
+	}
+	if _, ok := l.Get(2); ok {
+		t.Errorf("Get(2) succeeded after Trim(3)")
+	}
+
+	// A cursor pointing to trimmed elements skips to the start.
+	c.Seek(0)
+	l.Append("e")
+	if s := readAll(c); s != "de" {
+		t.Errorf("read %q; want %q", s, "de")
+	}
+	if c.Offset() != 5 {
+		t.Errorf("Offset() = %v; want %v", c.Offset(), 5)
+	}
+}
@@ -0,0 +1,85 @@
+package queue
+
+import "iter"
+
+// Merge returns an iterator that lazily merges queues whose elements are individually sorted
+// in ascending order as determined by cmp. Equal elements are yielded in the order of
+// the queues in qs. The queues are not modified.
+// Do not modify the queues while iterating; the iterator panics if it detects a modification.
+func Merge[T any](cmp func(a, b T) int, qs ...*Queue[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		m := merger[T]{cmp: cmp}
+		for k, q := range qs {
+			if !q.IsEmpty() {
+				m.cursors = append(m.cursors, mergeCursor[T]{q: q, order: k, version: q.version})
+			}
+		}
+		for i := len(m.cursors)/2 - 1; i >= 0; i-- {
+			m.down(i)
+		}
+
+		for len(m.cursors) > 0 {
+			c := &m.cursors[0]
+			if !yield(c.head()) {
+				return
+			}
+			for i := range m.cursors {
+				m.cursors[i].q.checkVersion(m.cursors[i].version)
+			}
+
+			c.index++
+			if c.index == c.q.length {
+				last := len(m.cursors) - 1
+				m.cursors[0] = m.cursors[last]
+				m.cursors = m.cursors[:last]
+			}
+			m.down(0)
+		}
+	}
+}
+
+// mergeCursor is the position of Merge in one of the queues.
+type mergeCursor[T any] struct {
+	q       *Queue[T]
+	index   int
+	order   int
+	version uint
+}
+
+// head returns the element at the cursor.
+func (c *mergeCursor[T]) head() T {
+	return c.q.buffer[c.q.wrap(c.q.head+c.index)]
+}
+
+// merger is a binary min-heap of cursors ordered by their head elements.
+type merger[T any] struct {
+	cmp     func(a, b T) int
+	cursors []mergeCursor[T]
+}
+
+// less reports whether the cursor i must be yielded before the cursor j.
+func (m *merger[T]) less(i, j int) bool {
+	if c := m.cmp(m.cursors[i].head(), m.cursors[j].head()); c != 0 {
+		return c < 0
+	}
+	return m.cursors[i].order < m.cursors[j].order
+}
+
+// down moves the cursor i down the heap until the heap property is restored.
+func (m *merger[T]) down(i int) {
+	n := len(m.cursors)
+	for {
+		smallest := i
+		if l := 2*i + 1; l < n && m.less(l, smallest) {
+			smallest = l
+		}
+		if r := 2*i + 2; r < n && m.less(r, smallest) {
+			smallest = r
+		}
+		if smallest == i {
+			return
+		}
+		m.cursors[i], m.cursors[smallest] = m.cursors[smallest], m.cursors[i]
+		i = smallest
+	}
+}
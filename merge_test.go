@@ -0,0 +1,59 @@
+package queue_test
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func ExampleMerge() {
+	var q1, q2, q3 queue.Queue[int]
+	q1.PushMany([]int{1, 4, 7})
+	q2.PushMany([]int{2, 5, 8})
+	q3.PushMany([]int{3, 6, 9})
+
+	for x := range queue.Merge(cmp.Compare[int], &q1, &q2, &q3) {
+		fmt.Print(x, " ")
+	}
+	fmt.Println()
+	// Output:
+	// 1 2 3 4 5 6 7 8 9
+}
+
+func TestMerge(t *testing.T) {
+	type event struct {
+		time  int
+		shard string
+	}
+	compareTime := func(a, b event) int { return cmp.Compare(a.time, b.time) }
+
+	var q1, q2, q3, empty queue.Queue[event]
+	for range 5 { // move the head so that the elements wrap around
+		q1.Push(event{})
+		q1.Pop()
+	}
+	q1.PushMany([]event{{1, "a"}, {3, "a"}, {3, "a"}, {7, "a"}, {9, "a"}})
+	q2.PushMany([]event{{2, "b"}, {3, "b"}})
+	q3.PushMany([]event{{0, "c"}, {10, "c"}})
+
+	actual := slices.Collect(queue.Merge(compareTime, &q1, &empty, &q2, &q3))
+	expected := []event{{0, "c"}, {1, "a"}, {2, "b"}, {3, "a"}, {3, "a"}, {3, "b"}, {7, "a"}, {9, "a"}, {10, "c"}}
+	if !slices.Equal(actual, expected) {
+		t.Errorf("actual: %v; want: %v", actual, expected)
+	}
+
+	// Stop in the middle.
+	var prefix []event
+	for e := range queue.Merge(compareTime, &q1, &q2, &q3) {
+		if e.time > 2 {
+			break
+		}
+		prefix = append(prefix, e)
+	}
+	if !slices.Equal(prefix, expected[:3]) {
+		t.Errorf("prefix: %v; want: %v", prefix, expected[:3])
+	}
+}
@@ -0,0 +1,176 @@
+package queue
+
+import "math/bits"
+
+// MinMaxHeap is a double-ended priority queue backed by a min-max heap.
+// Both the minimum and the maximum element can be peeked in O(1) and popped in O(log n),
+// which suits bounded buffers that evict from both ends, such as a top-K buffer with expiry.
+// MinMaxHeap is NOT safe for concurrent use.
+type MinMaxHeap[T any] struct {
+	cmp func(a, b T) int
+
+	// The elements in heap order. The elements on even levels (0, 3-6, 15-30, ...)
+	// are not greater than their descendants, and those on odd levels are not less
+	// than their descendants.
+	elements []T
+}
+
+// NewMinMaxHeap returns an empty heap ordered by cmp,
+// which returns a negative number if a < b, zero if a == b and a positive number if a > b.
+func NewMinMaxHeap[T any](cmp func(a, b T) int) *MinMaxHeap[T] {
+	return &MinMaxHeap[T]{cmp: cmp}
+}
+
+// Len returns the number of elements in the heap.
+func (h *MinMaxHeap[T]) Len() int {
+	return len(h.elements)
+}
+
+// IsEmpty returns true if the heap is empty.
+func (h *MinMaxHeap[T]) IsEmpty() bool {
+	return len(h.elements) == 0
+}
+
+// Push adds an element to the heap.
+func (h *MinMaxHeap[T]) Push(x T) {
+	h.elements = append(h.elements, x)
+	h.up(len(h.elements) - 1)
+}
+
+// PeekMin returns the minimum element without removing it.
+// If the heap is empty, PeekMin returns the zero value of T and false.
+func (h *MinMaxHeap[T]) PeekMin() (T, bool) {
+	if h.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+	return h.elements[0], true
+}
+
+// PeekMax returns the maximum element without removing it.
+// If the heap is empty, PeekMax returns the zero value of T and false.
+func (h *MinMaxHeap[T]) PeekMax() (T, bool) {
+	if h.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+	return h.elements[h.maxIndex()], true
+}
+
+// PopMin removes and returns the minimum element.
+// If the heap is empty, PopMin returns the zero value of T and false.
+func (h *MinMaxHeap[T]) PopMin() (T, bool) {
+	if h.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+	return h.removeAt(0), true
+}
+
+// PopMax removes and returns the maximum element.
+// If the heap is empty, PopMax returns the zero value of T and false.
+func (h *MinMaxHeap[T]) PopMax() (T, bool) {
+	if h.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+	return h.removeAt(h.maxIndex()), true
+}
+
+// maxIndex returns the index of the maximum element, which is the root
+// or one of its children. Caller must guarantee that the heap is not empty.
+func (h *MinMaxHeap[T]) maxIndex() int {
+	switch len(h.elements) {
+	case 1:
+		return 0
+	case 2:
+		return 1
+	}
+	if h.cmp(h.elements[1], h.elements[2]) >= 0 {
+		return 1
+	}
+	return 2
+}
+
+// removeAt removes and returns the element at i, which must be the root or one of its children.
+func (h *MinMaxHeap[T]) removeAt(i int) T {
+	x := h.elements[i]
+	last := len(h.elements) - 1
+	h.elements[i] = h.elements[last]
+	var zero T
+	h.elements[last] = zero
+	h.elements = h.elements[:last]
+	if i < last {
+		h.down(i)
+	}
+	return x
+}
+
+// ordered reports whether a must be closer to the root than b on a level of the given kind:
+// a < b on min levels and a > b on max levels.
+func (h *MinMaxHeap[T]) ordered(minLevel bool, a, b T) bool {
+	if minLevel {
+		return h.cmp(a, b) < 0
+	}
+	return h.cmp(a, b) > 0
+}
+
+func (h *MinMaxHeap[T]) swap(i, j int) {
+	h.elements[i], h.elements[j] = h.elements[j], h.elements[i]
+}
+
+// up moves the element at i toward the root to restore the heap order.
+func (h *MinMaxHeap[T]) up(i int) {
+	if i == 0 {
+		return
+	}
+	minLevel := isMinLevel(i)
+	p := (i - 1) / 2
+	if h.ordered(!minLevel, h.elements[i], h.elements[p]) {
+		// The element belongs to the levels of the other kind.
+		h.swap(i, p)
+		i, minLevel = p, !minLevel
+	}
+	// Bubble up through the grandparents on the levels of the same kind.
+	for i >= 3 {
+		g := ((i-1)/2 - 1) / 2
+		if !h.ordered(minLevel, h.elements[i], h.elements[g]) {
+			break
+		}
+		h.swap(i, g)
+		i = g
+	}
+}
+
+// down moves the element at i toward the leaves to restore the heap order.
+func (h *MinMaxHeap[T]) down(i int) {
+	minLevel := isMinLevel(i)
+	n := len(h.elements)
+	for {
+		// Find the most extreme of the children and grandchildren.
+		m := -1
+		for _, c := range [...]int{2*i + 1, 2*i + 2, 4*i + 3, 4*i + 4, 4*i + 5, 4*i + 6} {
+			if c < n && (m < 0 || h.ordered(minLevel, h.elements[c], h.elements[m])) {
+				m = c
+			}
+		}
+		if m < 0 || !h.ordered(minLevel, h.elements[m], h.elements[i]) {
+			return
+		}
+
+		h.swap(m, i)
+		if m <= 2*i+2 {
+			// A child is a leaf of the subtree on the other kind of level.
+			return
+		}
+		if p := (m - 1) / 2; h.ordered(!minLevel, h.elements[m], h.elements[p]) {
+			h.swap(m, p)
+		}
+		i = m
+	}
+}
+
+// isMinLevel reports whether the index i is on a min level of the heap.
+func isMinLevel(i int) bool {
+	return bits.Len(uint(i+1))%2 == 1
+}
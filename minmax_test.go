@@ -0,0 +1,75 @@
+package queue_test
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestMinMaxHeap(t *testing.T) {
+	h := queue.NewMinMaxHeap(cmp.Compare[int])
+	if _, ok := h.PopMin(); ok {
+		t.Errorf("PopMin() on empty heap returned ok")
+	}
+	if _, ok := h.PeekMax(); ok {
+		t.Errorf("PeekMax() on empty heap returned ok")
+	}
+
+	for _, x := range []int{3, 1, 4, 1, 5, 9, 2, 6} {
+		h.Push(x)
+	}
+	if x, _ := h.PeekMin(); x != 1 {
+		t.Errorf("PeekMin() = %v; want 1", x)
+	}
+	if x, _ := h.PeekMax(); x != 9 {
+		t.Errorf("PeekMax() = %v; want 9", x)
+	}
+
+	var mins, maxes []int
+	for h.Len() >= 2 {
+		x, _ := h.PopMin()
+		mins = append(mins, x)
+		y, _ := h.PopMax()
+		maxes = append(maxes, y)
+	}
+	if want := []int{1, 1, 2, 3}; !slices.Equal(mins, want) {
+		t.Errorf("PopMin() returned %v; want %v", mins, want)
+	}
+	if want := []int{9, 6, 5, 4}; !slices.Equal(maxes, want) {
+		t.Errorf("PopMax() returned %v; want %v", maxes, want)
+	}
+}
+
+func TestMinMaxHeap_Randomized(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	h := queue.NewMinMaxHeap(cmp.Compare[int])
+	var model []int // sorted
+
+	for range 10000 {
+		switch op := rng.Intn(3); {
+		case op == 0 || len(model) == 0:
+			x := rng.Intn(100)
+			h.Push(x)
+			i, _ := slices.BinarySearch(model, x)
+			model = slices.Insert(model, i, x)
+		case op == 1:
+			x, ok := h.PopMin()
+			if !ok || x != model[0] {
+				t.Fatalf("PopMin() = (%v, %v); want (%v, true)", x, ok, model[0])
+			}
+			model = model[1:]
+		default:
+			x, ok := h.PopMax()
+			if !ok || x != model[len(model)-1] {
+				t.Fatalf("PopMax() = (%v, %v); want (%v, true)", x, ok, model[len(model)-1])
+			}
+			model = model[:len(model)-1]
+		}
+		if h.Len() != len(model) {
+			t.Fatalf("Len() = %v; want %v", h.Len(), len(model))
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package queue
+
+import "time"
+
+// MLFQ is a multilevel feedback queue scheduler for jobs.
+// It has several levels, each with a time quantum; level 0 has the highest priority
+// and usually the shortest quantum. New jobs enter level 0, and Pop always serves
+// the highest-priority non-empty level. A job that uses up its whole quantum is demoted
+// to the next level when it is requeued, so long-running jobs sink while short and
+// interactive ones stay on top. To prevent starvation, all jobs are periodically
+// boosted back to level 0.
+// MLFQ is NOT safe for concurrent use.
+type MLFQ[T any] struct {
+	levels []Queue[T]
+	quanta []time.Duration
+
+	// The interval of priority boosting, or zero to disable it, and the time of the last boost.
+	boostPeriod time.Duration
+	lastBoost   time.Time
+
+	// The total number of jobs in all levels.
+	length int
+}
+
+// MLFQItem is a job handed out by Pop. Pass it back to Requeue if the job is not finished.
+type MLFQItem[T any] struct {
+	// The job.
+	Value T
+
+	// The level the job was popped from.
+	Level int
+
+	// The time quantum of the level. The consumer should run the job for at most this long.
+	Quantum time.Duration
+}
+
+// NewMLFQ returns an empty scheduler with one level per element of quanta.
+// If boostPeriod is positive, all jobs are moved back to level 0 every boostPeriod;
+// the boost is applied lazily by Pop.
+// It panics if quanta is empty or contains a non-positive quantum.
+func NewMLFQ[T any](quanta []time.Duration, boostPeriod time.Duration) *MLFQ[T] {
+	if len(quanta) == 0 {
+		panic("queue: MLFQ needs at least one level")
+	}
+	for _, quantum := range quanta {
+		if quantum <= 0 {
+			panic("queue: quantum must be positive")
+		}
+	}
+	return &MLFQ[T]{
+		levels:      make([]Queue[T], len(quanta)),
+		quanta:      append([]time.Duration(nil), quanta...),
+		boostPeriod: boostPeriod,
+		lastBoost:   time.Now(),
+	}
+}
+
+// Len returns the total number of jobs in all levels.
+func (m *MLFQ[T]) Len() int {
+	return m.length
+}
+
+// IsEmpty returns true if there are no jobs.
+func (m *MLFQ[T]) IsEmpty() bool {
+	return m.length == 0
+}
+
+// LenOf returns the number of jobs in the given level.
+func (m *MLFQ[T]) LenOf(level int) int {
+	if level < 0 || level >= len(m.levels) {
+		return 0
+	}
+	return m.levels[level].Len()
+}
+
+// Push adds a new job to the back of level 0.
+func (m *MLFQ[T]) Push(x T) {
+	m.levels[0].Push(x)
+	m.length++
+}
+
+// Pop removes and returns the job at the front of the highest-priority non-empty level.
+// If a priority boost is due, it is applied first.
+// If there are no jobs, Pop returns the zero value of MLFQItem and false.
+func (m *MLFQ[T]) Pop() (MLFQItem[T], bool) {
+	if m.boostPeriod > 0 {
+		if now := time.Now(); now.Sub(m.lastBoost) >= m.boostPeriod {
+			m.boost(now)
+		}
+	}
+
+	for level := range m.levels {
+		if x, ok := m.levels[level].Pop(); ok {
+			m.length--
+			return MLFQItem[T]{Value: x, Level: level, Quantum: m.quanta[level]}, true
+		}
+	}
+	return MLFQItem[T]{}, false
+}
+
+// Requeue puts back a job that was popped but has not finished, after it ran for used.
+// If the job used up its whole quantum, it is demoted to the next level
+// (it stays in the last level if it is already there); otherwise it returns to the back
+// of the level it was popped from.
+func (m *MLFQ[T]) Requeue(item MLFQItem[T], used time.Duration) {
+	level := min(max(item.Level, 0), len(m.levels)-1)
+	if used >= m.quanta[level] && level < len(m.levels)-1 {
+		level++
+	}
+	m.levels[level].Push(item.Value)
+	m.length++
+}
+
+// Boost moves all jobs back to level 0 immediately, keeping their relative order
+// within each level, and restarts the boost period.
+func (m *MLFQ[T]) Boost() {
+	m.boost(time.Now())
+}
+
+func (m *MLFQ[T]) boost(now time.Time) {
+	for level := 1; level < len(m.levels); level++ {
+		m.levels[0].PushQueue(&m.levels[level])
+		m.levels[level].Truncate(0)
+	}
+	m.lastBoost = now
+}
@@ -0,0 +1,75 @@
+package queue_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestMLFQ(t *testing.T) {
+	// Setup
+	m := queue.NewMLFQ[string]([]time.Duration{10 * time.Millisecond, 40 * time.Millisecond}, 0)
+	m.Push("long")
+	m.Push("short")
+
+	// Exercise & Verify
+	item, ok := m.Pop()
+	if !ok || item.Value != "long" || item.Level != 0 || item.Quantum != 10*time.Millisecond {
+		t.Fatalf("Pop() = (%+v, %v); want long at level 0", item, ok)
+	}
+	// "long" uses up its quantum and is demoted.
+	m.Requeue(item, 10*time.Millisecond)
+	if m.LenOf(0) != 1 || m.LenOf(1) != 1 {
+		t.Errorf("LenOf(0), LenOf(1) = %v, %v; want 1, 1", m.LenOf(0), m.LenOf(1))
+	}
+
+	item, _ = m.Pop()
+	if item.Value != "short" || item.Level != 0 {
+		t.Fatalf("Pop() = %+v; want short at level 0", item)
+	}
+	// "short" yields before its quantum expires and stays at level 0.
+	m.Requeue(item, time.Millisecond)
+
+	item, _ = m.Pop()
+	if item.Value != "short" || item.Level != 0 {
+		t.Fatalf("Pop() = %+v; want short at level 0", item)
+	}
+
+	item, _ = m.Pop()
+	if item.Value != "long" || item.Level != 1 || item.Quantum != 40*time.Millisecond {
+		t.Fatalf("Pop() = %+v; want long at level 1", item)
+	}
+	// The last level is the floor.
+	m.Requeue(item, time.Second)
+	if m.LenOf(1) != 1 {
+		t.Errorf("LenOf(1) = %v; want 1", m.LenOf(1))
+	}
+
+	// Boosting moves it back to the top.
+	m.Boost()
+	if m.LenOf(0) != 1 || m.LenOf(1) != 0 || m.Len() != 1 {
+		t.Errorf("after Boost: LenOf(0), LenOf(1), Len() = %v, %v, %v; want 1, 0, 1",
+			m.LenOf(0), m.LenOf(1), m.Len())
+	}
+}
+
+func TestMLFQ_BoostPeriod(t *testing.T) {
+	// Setup
+	m := queue.NewMLFQ[int]([]time.Duration{time.Millisecond, time.Millisecond}, 20*time.Millisecond)
+	m.Push(1)
+	item, _ := m.Pop()
+	m.Requeue(item, time.Millisecond)
+	m.Push(2)
+
+	// Exercise
+	time.Sleep(30 * time.Millisecond)
+	first, _ := m.Pop()
+	second, _ := m.Pop()
+
+	// Verify
+	// The boosted job is appended to level 0 behind the job already there.
+	if first.Value != 2 || first.Level != 0 || second.Value != 1 || second.Level != 0 {
+		t.Errorf("Pop() after boost period = %+v, %+v; want 2 and 1 at level 0", first, second)
+	}
+}
@@ -0,0 +1,63 @@
+package queue
+
+import "sync/atomic"
+
+// MPSC is an unbounded lock-free FIFO queue for multiple producers and a single consumer,
+// suitable for loggers and event loops where many goroutines push and exactly one goroutine drains.
+// It is a linked list of nodes in which Push only swaps the pointer to the last node,
+// so producers never wait for each other or for the consumer.
+//
+// Push is safe for concurrent use by multiple goroutines, but Pop must be called
+// from one goroutine at a time.
+// The zero value for MPSC is an empty queue ready to use.
+// An MPSC must not be copied after first use.
+type MPSC[T any] struct {
+	// The last node, swapped by producers, or nil if nothing has been pushed yet,
+	// in which case the last node is stub.
+	last atomic.Pointer[mpscNode[T]]
+
+	// The node whose successor holds the front element, or nil to mean stub.
+	// It is accessed only by the consumer.
+	first *mpscNode[T]
+
+	// The initial node, which holds no element.
+	stub mpscNode[T]
+}
+
+type mpscNode[T any] struct {
+	next  atomic.Pointer[mpscNode[T]]
+	value T
+}
+
+// Push adds an element to the back of the queue.
+func (q *MPSC[T]) Push(x T) {
+	n := &mpscNode[T]{value: x}
+	prev := q.last.Swap(n)
+	if prev == nil {
+		prev = &q.stub
+	}
+	// Between the swap and this store, the consumer cannot see n or the nodes pushed after it.
+	prev.next.Store(n)
+}
+
+// Pop removes and returns the element at the front of the queue.
+// If the queue is empty, Pop returns the zero value of T and false.
+// Pop may also return false for a short moment while a concurrent Push is in progress,
+// even if other elements have been pushed after it.
+func (q *MPSC[T]) Pop() (T, bool) {
+	if q.first == nil {
+		q.first = &q.stub
+	}
+	next := q.first.next.Load()
+	if next == nil {
+		var zero T
+		return zero, false
+	}
+
+	// next becomes the new empty head node.
+	x := next.value
+	var zero T
+	next.value = zero
+	q.first = next
+	return x, true
+}
@@ -0,0 +1,62 @@
+package queue_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestMPSC(t *testing.T) {
+	var q queue.MPSC[int]
+	if _, ok := q.Pop(); ok {
+		t.Errorf("Pop() on empty MPSC returned ok")
+	}
+
+	for i := range 5 {
+		q.Push(i)
+	}
+	for i := range 5 {
+		if x, ok := q.Pop(); !ok || x != i {
+			t.Errorf("Pop() = (%v, %v); want (%v, true)", x, ok, i)
+		}
+	}
+	if _, ok := q.Pop(); ok {
+		t.Errorf("Pop() on drained MPSC returned ok")
+	}
+}
+
+func TestMPSC_Concurrent(t *testing.T) {
+	// Setup
+	var q queue.MPSC[int]
+	const producers, perProducer = 8, 10000
+
+	// Exercise
+	var wg sync.WaitGroup
+	for p := range producers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perProducer {
+				q.Push(p*perProducer + i)
+			}
+		}()
+	}
+
+	// Verify
+	// Elements from each producer must come out in the order they were pushed.
+	next := make([]int, producers)
+	for received := 0; received < producers*perProducer; {
+		x, ok := q.Pop()
+		if !ok {
+			continue
+		}
+		p, i := x/perProducer, x%perProducer
+		if i != next[p] {
+			t.Fatalf("producer %v: got element %v; want %v", p, i, next[p])
+		}
+		next[p]++
+		received++
+	}
+	wg.Wait()
+}
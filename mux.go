@@ -0,0 +1,81 @@
+package queue
+
+// Mux multiplexes multiple named queues and pops from them in round-robin order,
+// skipping empty ones, so that a single consumer can serve many producers fairly.
+// The zero value for Mux is an empty multiplexer ready to use.
+// Mux is NOT safe for concurrent use.
+type Mux[T any] struct {
+	names  []string
+	queues []Queue[T]
+
+	// The index of each name in names and queues.
+	indexes map[string]int
+
+	// The index of the queue to try first on the next Pop.
+	// Invariant: 0 <= next < len(queues)  (len(queues) != 0)
+	next int
+
+	// The total number of elements in all queues.
+	length int
+}
+
+// Len returns the total number of elements in all queues.
+func (m *Mux[T]) Len() int {
+	return m.length
+}
+
+// IsEmpty returns true if all queues are empty.
+func (m *Mux[T]) IsEmpty() bool {
+	return m.length == 0
+}
+
+// LenOf returns the number of elements in the queue with the given name.
+func (m *Mux[T]) LenOf(name string) int {
+	i, ok := m.indexes[name]
+	if !ok {
+		return 0
+	}
+	return m.queues[i].Len()
+}
+
+// Names returns the names of all queues in the order they were created.
+func (m *Mux[T]) Names() []string {
+	return append([]string(nil), m.names...)
+}
+
+// Push adds an element to the back of the queue with the given name.
+// The queue is created if it does not exist.
+func (m *Mux[T]) Push(name string, x T) {
+	i, ok := m.indexes[name]
+	if !ok {
+		if m.indexes == nil {
+			m.indexes = make(map[string]int)
+		}
+		i = len(m.queues)
+		m.indexes[name] = i
+		m.names = append(m.names, name)
+		m.queues = append(m.queues, Queue[T]{})
+	}
+	m.queues[i].Push(x)
+	m.length++
+}
+
+// Pop removes and returns the element at the front of the next non-empty queue
+// in round-robin order, together with the name of the queue.
+// If all queues are empty, Pop returns the zero value of T, an empty name and false.
+func (m *Mux[T]) Pop() (T, string, bool) {
+	if m.IsEmpty() {
+		var zero T
+		return zero, "", false
+	}
+
+	for k := range len(m.queues) {
+		i := (m.next + k) % len(m.queues)
+		if x, ok := m.queues[i].Pop(); ok {
+			m.next = (i + 1) % len(m.queues)
+			m.length--
+			return x, m.names[i], true
+		}
+	}
+	panic("queue: Mux is inconsistent: all queues are empty while length != 0")
+}
@@ -0,0 +1,54 @@
+package queue_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func ExampleMux() {
+	var m queue.Mux[int]
+	m.Push("a", 1)
+	m.Push("a", 2)
+	m.Push("a", 3)
+	m.Push("b", 10)
+	m.Push("c", 100)
+	m.Push("c", 200)
+
+	for !m.IsEmpty() {
+		x, name, _ := m.Pop()
+		fmt.Println(name, x)
+	}
+	// Output:
+	// a 1
+	// b 10
+	// c 100
+	// a 2
+	// c 200
+	// a 3
+}
+
+func TestMux(t *testing.T) {
+	var m queue.Mux[int]
+	if _, _, ok := m.Pop(); ok {
+		t.Errorf("Pop() on empty Mux returned ok")
+	}
+
+	m.Push("a", 1)
+	m.Push("b", 2)
+	m.Pop()
+	m.Push("a", 3)
+
+	// "b" comes first because "a" was served last time.
+	if x, name, ok := m.Pop(); x != 2 || name != "b" || !ok {
+		t.Errorf("Pop() = %v, %q, %v; want %v, %q, %v", x, name, ok, 2, "b", true)
+	}
+	if m.Len() != 1 || m.LenOf("a") != 1 || m.LenOf("b") != 0 || m.LenOf("c") != 0 {
+		t.Errorf("Len() = %v, LenOf(a) = %v, LenOf(b) = %v", m.Len(), m.LenOf("a"), m.LenOf("b"))
+	}
+	if names := m.Names(); !slices.Equal(names, []string{"a", "b"}) {
+		t.Errorf("Names() = %v; want %v", names, []string{"a", "b"})
+	}
+}
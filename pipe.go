@@ -0,0 +1,38 @@
+package queue
+
+import "context"
+
+// The maximum number of elements Pipe moves at once.
+const pipeBatchSize = 64
+
+// Pipe moves elements from src to dst, transforming each of them with fn.
+// Elements are moved in batches to reduce lock contention. Pipes can be chained
+// to build a multi-stage pipeline with a goroutine per stage.
+//
+// When src is closed and drained, Pipe closes dst and returns nil,
+// so that closing the first queue shuts down the whole pipeline in order.
+// If ctx is done, Pipe returns the error of ctx, and if dst is closed,
+// Pipe returns ErrClosed; in both cases, the elements of the last batch may be lost.
+func Pipe[In, Out any](ctx context.Context, src *SyncQueue[In], dst *SyncQueue[Out], fn func(In) Out) error {
+	in := make([]In, pipeBatchSize)
+	out := make([]Out, pipeBatchSize)
+	for {
+		n, err := src.popBatchWait(ctx, in)
+		if err == ErrClosed {
+			dst.Close()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for i, x := range in[:n] {
+			out[i] = fn(x)
+		}
+		if err := dst.PushMany(out[:n]); err != nil {
+			return err
+		}
+		clear(in[:n])
+		clear(out[:n])
+	}
+}
@@ -0,0 +1,65 @@
+package queue_test
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestPipe(t *testing.T) {
+	// Setup
+	var numbers queue.SyncQueue[int]
+	var squares queue.SyncQueue[int]
+	var strs queue.SyncQueue[string]
+	errs := make(chan error, 2)
+	go func() {
+		errs <- queue.Pipe(context.Background(), &numbers, &squares, func(x int) int { return x * x })
+	}()
+	go func() {
+		errs <- queue.Pipe(context.Background(), &squares, &strs, strconv.Itoa)
+	}()
+
+	// Exercise
+	var want []string
+	for i := range 200 {
+		numbers.Push(i)
+		want = append(want, strconv.Itoa(i*i))
+	}
+	numbers.Close()
+
+	var got []string
+	for {
+		s, err := strs.PopWait(context.Background())
+		if err == queue.ErrClosed {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, s)
+	}
+
+	// Verify
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Errorf("Pipe() = %v; want nil", err)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
+
+func TestPipe_Canceled(t *testing.T) {
+	var src, dst queue.SyncQueue[int]
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := queue.Pipe(ctx, &src, &dst, func(x int) int { return x })
+	if err != context.Canceled {
+		t.Errorf("Pipe() = %v; want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,62 @@
+package queue
+
+import (
+	"fmt"
+	"math/bits"
+	"sync"
+
+	"github.com/nojima/queue-go/ringbuf"
+)
+
+// Pool is an Allocator that keeps released buffers for reuse.
+// A queue that uses a pool returns the buffers it no longer needs when it grows, shrinks
+// or is reset, and other queues using the same pool take them instead of allocating.
+// This reduces the GC pressure of programs that create many short-lived queues.
+// The zero value for Pool is an empty pool ready to use.
+// Pool is safe for concurrent use, while each queue is still not.
+type Pool[T any] struct {
+	// classes[k] holds *[]T pointing to buffers whose capacity is at least 1<<k.
+	classes [bits.UintSize]sync.Pool
+
+	// The *[]T emptied by Alloc, reused by Free so that it does not allocate one per buffer.
+	headers sync.Pool
+}
+
+// Alloc returns a buffer of length n, reusing a released one if available.
+// Buffers are pooled by capacity rounded up to a power of 2, so n need not be a power of 2.
+// If n is 0, Alloc returns nil. If n is negative, it panics.
+func (p *Pool[T]) Alloc(n int) []T {
+	if n < 0 {
+		panic(fmt.Sprintf("queue: negative buffer length: n=%d", n))
+	}
+	if n == 0 {
+		return nil
+	}
+
+	capacity := ringbuf.CapacityFor(n)
+	if v := p.classes[bits.Len(uint(capacity))-1].Get(); v != nil {
+		header := v.(*[]T)
+		buffer := *header
+		*header = nil
+		p.headers.Put(header)
+		return buffer[:n]
+	}
+	return make([]T, n, capacity)
+}
+
+// Free clears buffer and keeps it for reuse. Buffers with zero capacity are ignored.
+func (p *Pool[T]) Free(buffer []T) {
+	if cap(buffer) == 0 {
+		return
+	}
+	buffer = buffer[:cap(buffer)]
+	clear(buffer)
+	header, _ := p.headers.Get().(*[]T)
+	if header == nil {
+		header = new([]T)
+	}
+	*header = buffer
+	// File the buffer under the largest power of 2 that does not exceed its capacity,
+	// so that every buffer in a class can hold the lengths Alloc serves from it.
+	p.classes[bits.Len(uint(len(buffer)))-1].Put(header)
+}
@@ -0,0 +1,92 @@
+package queue_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestPool(t *testing.T) {
+	var pool queue.Pool[int]
+	for k := range 100 {
+		var q queue.Queue[int]
+		q.UseAllocator(&pool)
+
+		var expected []int
+		for i := range k {
+			q.Push(i)
+			expected = append(expected, i)
+		}
+		if actual := slices.Collect(q.All()); !slices.Equal(actual, expected) {
+			t.Errorf("actual: %v; want: %v", actual, expected)
+		}
+
+		q.Reset()
+		if q.Len() != 0 || q.Cap() != 0 {
+			t.Errorf("Len() = %v, Cap() = %v; want 0, 0", q.Len(), q.Cap())
+		}
+	}
+}
+
+func TestPool_noAllocs(t *testing.T) {
+	// Setup
+	var pool queue.Pool[int]
+	pool.Free(pool.Alloc(64))
+
+	// Exercise
+	allocs := testing.AllocsPerRun(100, func() {
+		pool.Free(pool.Alloc(64))
+	})
+
+	// Verify
+	// A garbage collection during the run may empty the pool, so allow an occasional allocation.
+	if allocs >= 1 {
+		t.Errorf("Alloc and Free allocated %v times per run; want 0", allocs)
+	}
+}
+
+func TestPool_Alloc(t *testing.T) {
+	var pool queue.Pool[int]
+
+	if b := pool.Alloc(0); len(b) != 0 {
+		t.Errorf("Alloc(0) returned a buffer of length %v; want 0", len(b))
+	}
+	pool.Free(nil)
+
+	// A buffer of a length that is not a power of 2 must only be reused for lengths it can hold.
+	b := pool.Alloc(6)
+	if len(b) != 6 || cap(b) < 6 {
+		t.Errorf("Alloc(6) returned len %v, cap %v; want len 6, cap >= 6", len(b), cap(b))
+	}
+	pool.Free(b)
+	for _, n := range []int{2, 5, 8, 9} {
+		b := pool.Alloc(n)
+		if len(b) != n || cap(b) < n {
+			t.Errorf("Alloc(%v) returned len %v, cap %v", n, len(b), cap(b))
+		}
+		for i := range b {
+			if b[i] != 0 {
+				t.Errorf("Alloc(%v) returned a buffer that is not cleared", n)
+				break
+			}
+		}
+		pool.Free(b)
+	}
+
+	// An odd-capacity buffer from elsewhere is filed under a class it can serve.
+	pool.Free(make([]int, 6))
+	if b := pool.Alloc(4); len(b) != 4 {
+		t.Errorf("Alloc(4) returned len %v; want 4", len(b))
+	}
+}
+
+func TestPool_AllocNegative(t *testing.T) {
+	var pool queue.Pool[int]
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Alloc(-1) did not panic")
+		}
+	}()
+	pool.Alloc(-1)
+}
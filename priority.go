@@ -0,0 +1,167 @@
+package queue
+
+import "time"
+
+// PriorityQueue is a priority queue backed by a binary heap.
+// Pop returns the element with the smallest priority first; elements with equal priorities
+// are returned in the order they were pushed.
+// PriorityQueue is NOT safe for concurrent use.
+type PriorityQueue[T any] struct {
+	// The comparison of elements, or nil if the queue uses aging.
+	cmp func(a, b T) int
+
+	// The priority of elements and the function of aging, used if cmp is nil.
+	priority func(T) float64
+	aging    func(priority float64, age time.Duration) float64
+
+	// The entries, forming a binary heap if cmp is not nil and in no particular order otherwise.
+	entries []priorityEntry[T]
+
+	// The sequence number of the next pushed element.
+	seq uint64
+}
+
+type priorityEntry[T any] struct {
+	value T
+
+	// The priority and the time the element was pushed. They are used only if the queue uses aging.
+	priority float64
+	pushed   time.Time
+
+	seq uint64
+}
+
+// NewPriorityQueue returns an empty priority queue ordered by cmp,
+// which returns a negative number if a has a smaller priority than b.
+func NewPriorityQueue[T any](cmp func(a, b T) int) *PriorityQueue[T] {
+	return &PriorityQueue[T]{cmp: cmp}
+}
+
+// NewAgingPriorityQueue returns an empty priority queue with aging.
+// The priority of an element is given by priority, where smaller values are served first.
+// While the element waits in the queue, its effective priority is aging(p, age),
+// where p is its priority and age is the time since it was pushed, so that aging can improve
+// the priority of low-priority elements until they are eventually served even if
+// high-priority ones keep coming.
+// Since the effective priorities can change the relative order of elements at any time,
+// Pop and Peek recompute them for all elements and take O(n) time.
+func NewAgingPriorityQueue[T any](priority func(T) float64, aging func(priority float64, age time.Duration) float64) *PriorityQueue[T] {
+	return &PriorityQueue[T]{priority: priority, aging: aging}
+}
+
+// Len returns the number of elements in the queue.
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.entries)
+}
+
+// IsEmpty returns true if the queue is empty.
+func (pq *PriorityQueue[T]) IsEmpty() bool {
+	return len(pq.entries) == 0
+}
+
+// Push adds an element to the queue.
+func (pq *PriorityQueue[T]) Push(x T) {
+	e := priorityEntry[T]{value: x, seq: pq.seq}
+	pq.seq++
+	if pq.cmp == nil {
+		e.priority = pq.priority(x)
+		e.pushed = time.Now()
+		pq.entries = append(pq.entries, e)
+		return
+	}
+	pq.entries = append(pq.entries, e)
+	pq.up(len(pq.entries) - 1)
+}
+
+// Pop removes and returns the element with the smallest priority.
+// If the queue is empty, Pop returns the zero value of T and false.
+func (pq *PriorityQueue[T]) Pop() (T, bool) {
+	if pq.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+
+	i := 0
+	if pq.cmp == nil {
+		i = pq.agedMin()
+	}
+	x := pq.entries[i].value
+	last := len(pq.entries) - 1
+	pq.entries[i] = pq.entries[last]
+	pq.entries[last] = priorityEntry[T]{}
+	pq.entries = pq.entries[:last]
+	if pq.cmp != nil {
+		pq.down(0)
+	}
+	return x, true
+}
+
+// Peek returns the element with the smallest priority without removing it.
+// If the queue is empty, Peek returns the zero value of T and false.
+func (pq *PriorityQueue[T]) Peek() (T, bool) {
+	if pq.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+	if pq.cmp == nil {
+		return pq.entries[pq.agedMin()].value, true
+	}
+	return pq.entries[0].value, true
+}
+
+// agedMin returns the index of the entry with the smallest effective priority,
+// breaking ties by push order. Caller must guarantee that the queue is not empty.
+func (pq *PriorityQueue[T]) agedMin() int {
+	now := time.Now()
+	best := 0
+	bestKey := pq.aging(pq.entries[0].priority, now.Sub(pq.entries[0].pushed))
+	for i := 1; i < len(pq.entries); i++ {
+		e := &pq.entries[i]
+		key := pq.aging(e.priority, now.Sub(e.pushed))
+		if key < bestKey || (key == bestKey && e.seq < pq.entries[best].seq) {
+			best, bestKey = i, key
+		}
+	}
+	return best
+}
+
+// less reports whether the entry i must be popped before the entry j.
+// It is used only if the queue does not use aging.
+func (pq *PriorityQueue[T]) less(i, j int) bool {
+	a, b := &pq.entries[i], &pq.entries[j]
+	if c := pq.cmp(a.value, b.value); c != 0 {
+		return c < 0
+	}
+	return a.seq < b.seq
+}
+
+// up moves the entry i up the heap until the heap property is restored.
+func (pq *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(i, parent) {
+			return
+		}
+		pq.entries[i], pq.entries[parent] = pq.entries[parent], pq.entries[i]
+		i = parent
+	}
+}
+
+// down moves the entry i down the heap until the heap property is restored.
+func (pq *PriorityQueue[T]) down(i int) {
+	n := len(pq.entries)
+	for {
+		smallest := i
+		if l := 2*i + 1; l < n && pq.less(l, smallest) {
+			smallest = l
+		}
+		if r := 2*i + 2; r < n && pq.less(r, smallest) {
+			smallest = r
+		}
+		if smallest == i {
+			return
+		}
+		pq.entries[i], pq.entries[smallest] = pq.entries[smallest], pq.entries[i]
+		i = smallest
+	}
+}
@@ -0,0 +1,100 @@
+package queue_test
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	type task struct {
+		priority int
+		name     string
+	}
+	pq := queue.NewPriorityQueue(func(a, b task) int { return cmp.Compare(a.priority, b.priority) })
+	if _, ok := pq.Pop(); ok {
+		t.Errorf("Pop() on empty queue returned ok")
+	}
+
+	for _, x := range []task{{3, "a"}, {1, "b"}, {4, "c"}, {1, "d"}, {5, "e"}, {9, "f"}, {2, "g"}} {
+		pq.Push(x)
+	}
+	if x, _ := pq.Peek(); x.name != "b" {
+		t.Errorf("Peek() = %v; want b", x)
+	}
+
+	var names []string
+	for !pq.IsEmpty() {
+		x, _ := pq.Pop()
+		names = append(names, x.name)
+	}
+	// Elements with equal priorities keep the push order.
+	if want := []string{"b", "d", "g", "a", "c", "e", "f"}; !slices.Equal(names, want) {
+		t.Errorf("popped %v; want %v", names, want)
+	}
+}
+
+func TestPriorityQueue_Aging(t *testing.T) {
+	// Setup
+	// The priority improves by 1 per millisecond.
+	pq := queue.NewAgingPriorityQueue(
+		func(x int) float64 { return float64(x) },
+		func(p float64, age time.Duration) float64 { return p - float64(age.Milliseconds()) },
+	)
+	pq.Push(10)
+	time.Sleep(30 * time.Millisecond)
+	pq.Push(0)
+	pq.Push(50)
+
+	// Exercise
+	var popped []int
+	for !pq.IsEmpty() {
+		x, _ := pq.Pop()
+		popped = append(popped, x)
+	}
+
+	// Verify
+	// 10 has waited long enough to overtake 0, while 50 has not waited at all.
+	if want := []int{10, 0, 50}; !slices.Equal(popped, want) {
+		t.Errorf("popped %v; want %v", popped, want)
+	}
+}
+
+func TestPriorityQueue_AgingFunction(t *testing.T) {
+	// Setup
+	// An element that has waited 20 milliseconds gets the highest priority,
+	// which a linear rate cannot express.
+	pq := queue.NewAgingPriorityQueue(
+		func(x int) float64 { return float64(x) },
+		func(p float64, age time.Duration) float64 {
+			if age >= 20*time.Millisecond {
+				return math.Inf(-1)
+			}
+			return p
+		},
+	)
+	pq.Push(50)
+	time.Sleep(30 * time.Millisecond)
+	pq.Push(10)
+	pq.Push(5)
+
+	// Exercise
+	peeked, _ := pq.Peek()
+	var popped []int
+	for !pq.IsEmpty() {
+		x, _ := pq.Pop()
+		popped = append(popped, x)
+	}
+
+	// Verify
+	if peeked != 50 {
+		t.Errorf("Peek() = %v; want 50", peeked)
+	}
+	if want := []int{50, 5, 10}; !slices.Equal(popped, want) {
+		t.Errorf("popped %v; want %v", popped, want)
+	}
+}
@@ -3,7 +3,11 @@ package queue
 import (
 	"fmt"
 	"iter"
-	"math/bits"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/nojima/queue-go/ringbuf"
 )
 
 // Queue is a FIFO queue backed by a circular buffer.
@@ -23,6 +27,17 @@ type Queue[T any] struct {
 	// The circular buffer to store elements.
 	// Invariant: len(buffer) is a power of 2 or zero
 	buffer []T
+
+	// The modification counter, incremented by every operation that modifies the queue.
+	// Iterators use it to detect modifications during iteration.
+	version uint
+
+	// The detector of overlapping calls, enabled by the queuerace build tag.
+	guard guard
+
+	// The optional state such as statistics, hooks and the allocator,
+	// or nil if none of it is enabled.
+	ext *extensions[T]
 }
 
 // Len returns the number of elements in the queue.
@@ -30,6 +45,11 @@ func (q *Queue[T]) Len() int {
 	return q.length
 }
 
+// Cap returns the number of elements the queue can hold without reallocating its buffer.
+func (q *Queue[T]) Cap() int {
+	return len(q.buffer)
+}
+
 // IsEmpty returns true if the queue is empty.
 func (q *Queue[T]) IsEmpty() bool {
 	return q.length == 0
@@ -37,17 +57,36 @@ func (q *Queue[T]) IsEmpty() bool {
 
 // Push adds an element to the back of the queue.
 func (q *Queue[T]) Push(x T) {
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("Push")
+		defer q.guard.exit()
+	}
+
 	if q.remainingCapacity() == 0 {
 		q.reserve(len(q.buffer) + 1)
 	}
 
 	q.buffer[q.wrap(q.head+q.length)] = x
 	q.length++
+	q.added(1)
 }
 
 // PushMany adds multiple elements to the back of the queue.
 // PushMany is more efficient than calling Push multiple times.
 func (q *Queue[T]) PushMany(xs []T) {
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("PushMany")
+		defer q.guard.exit()
+	}
+
 	if q.remainingCapacity() < len(xs) {
 		q.reserve(q.length + len(xs))
 	}
@@ -57,6 +96,100 @@ func (q *Queue[T]) PushMany(xs []T) {
 	copy(q.buffer, xs[n:])
 
 	q.length += len(xs)
+	q.added(len(xs))
+}
+
+// PushQueue adds all elements of other to the back of the queue, keeping their order.
+// The elements are moved with a few bulk copies. other is not modified,
+// and it may be the same queue as q.
+func (q *Queue[T]) PushQueue(other *Queue[T]) {
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("PushQueue")
+		defer q.guard.exit()
+	}
+
+	if q.remainingCapacity() < other.length {
+		q.reserve(q.length + other.length)
+	}
+
+	first, second := other.segments()
+	q.copyIn(q.length, first)
+	q.copyIn(q.length+len(first), second)
+	q.length += len(first) + len(second)
+	q.added(len(first) + len(second))
+}
+
+// CopyTo replaces the elements of dst with the elements of q, keeping their order.
+// The elements are copied with a few bulk copies, and the buffer of dst is reused
+// if it is large enough. To append to dst instead, use dst.PushQueue(q).
+func (q *Queue[T]) CopyTo(dst *Queue[T]) {
+	if dst == q {
+		return
+	}
+	dst.Truncate(0)
+	dst.PushQueue(q)
+}
+
+// SwapContents exchanges the elements and buffers of q and other in constant time.
+// It enables double buffering: producers fill one queue while a consumer drains the other,
+// and then the two are swapped. The allocators and the adaptive capacity move with the buffers,
+// so that each buffer is eventually released to the allocator it came from.
+// The statistics and hooks stay with each queue.
+func (q *Queue[T]) SwapContents(other *Queue[T]) {
+	q.version++
+	other.version++
+	if debug {
+		defer q.CheckInvariants()
+		defer other.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("SwapContents")
+		defer q.guard.exit()
+		if other != q {
+			other.guard.enter("SwapContents")
+			defer other.guard.exit()
+		}
+	}
+
+	q.head, other.head = other.head, q.head
+	q.length, other.length = other.length, q.length
+	q.buffer, other.buffer = other.buffer, q.buffer
+	if q.ext != nil || other.ext != nil {
+		a, b := q.ensureExtensions(), other.ensureExtensions()
+		a.allocator, b.allocator = b.allocator, a.allocator
+		a.adaptive, b.adaptive = b.adaptive, a.adaptive
+		for _, r := range [...]*Queue[T]{q, other} {
+			if r.ext.stats != nil {
+				r.ext.stats.Peak = max(r.ext.stats.Peak, r.length)
+			}
+			r.checkWatermarks()
+		}
+	}
+}
+
+// PushSorted inserts an element at its sorted position in a queue sorted in ascending order
+// as determined by cmp, after any equal elements, and returns its logical index.
+// The position is found by binary search and the element is inserted by shifting whichever
+// side is shorter. If the element is not less than the last one, as is common in mostly
+// sorted streams, it is simply pushed to the back.
+func (q *Queue[T]) PushSorted(x T, cmp func(a, b T) int) int {
+	if q.IsEmpty() || cmp(q.buffer[q.wrap(q.head+q.length-1)], x) <= 0 {
+		q.Push(x)
+		return q.length - 1
+	}
+
+	i, _ := BinarySearchFunc(q, x, func(e, target T) int {
+		if cmp(e, target) <= 0 {
+			return -1
+		}
+		return 1
+	})
+	q.InsertAt(i, x)
+	return i
 }
 
 // Pop removes and returns the element at the front of the queue.
@@ -67,12 +200,64 @@ func (q *Queue[T]) Pop() (T, bool) {
 		return zero, false
 	}
 
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("Pop")
+		defer q.guard.exit()
+	}
+
 	x := q.buffer[q.head]
 	q.head = q.wrap(q.head + 1)
 	q.length--
+	q.removed(1)
 	return x, true
 }
 
+// PopIf removes and returns the element at the front of the queue only if it satisfies pred.
+// If the queue is empty or the element does not satisfy pred, PopIf returns
+// the zero value of T and false without modifying the queue.
+func (q *Queue[T]) PopIf(pred func(T) bool) (T, bool) {
+	if q.IsEmpty() || !pred(q.buffer[q.head]) {
+		var zero T
+		return zero, false
+	}
+	return q.Pop()
+}
+
+// PopWhile returns an iterator that removes and yields the elements at the front of the queue
+// while they satisfy pred. Each element is removed just before it is yielded,
+// so the elements after a break of the loop remain in the queue.
+// Unlike All, the queue may be modified while iterating.
+func (q *Queue[T]) PopWhile(pred func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			x, ok := q.PopIf(pred)
+			if !ok || !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+// EvictOlderThan removes the leading elements whose timestamps, as returned by ts,
+// are before cutoff, and returns the number of removed elements.
+// It stops at the first element that is not older than cutoff, so the elements are expected
+// to be pushed in timestamp order, as in a sliding window. The removed elements are
+// discarded in bulk instead of being popped one by one.
+func (q *Queue[T]) EvictOlderThan(cutoff time.Time, ts func(T) time.Time) int {
+	n := 0
+	for n < q.length && ts(q.buffer[q.wrap(q.head+n)]).Before(cutoff) {
+		n++
+	}
+	if n > 0 {
+		q.consume(n)
+	}
+	return n
+}
+
 // Peek returns the element at the front of the queue without removing it.
 // If the queue is empty, Peek returns the zero value of T and false.
 func (q *Queue[T]) Peek() (T, bool) {
@@ -85,27 +270,56 @@ func (q *Queue[T]) Peek() (T, bool) {
 }
 
 // All returns an iterator over all elements in the queue.
-// Do not modify the queue while iterating.
+// Do not modify the queue while iterating; the iterator panics if it detects a modification.
 func (q *Queue[T]) All() iter.Seq[T] {
 	return func(yield func(T) bool) {
 		head := q.head
+		version := q.version
 		for i := range q.length {
 			if !yield(q.buffer[q.wrap(head+i)]) {
 				break
 			}
+			q.checkVersion(version)
 		}
 	}
 }
 
 // Backward returns an iterator over all elements in the queue in reverse order (newest first).
-// Do not modify the queue while iterating.
+// Do not modify the queue while iterating; the iterator panics if it detects a modification.
 func (q *Queue[T]) Backward() iter.Seq[T] {
 	return func(yield func(T) bool) {
 		last := q.head + q.length - 1
+		version := q.version
 		for i := range q.length {
 			if !yield(q.buffer[q.wrap(last-i)]) {
 				break
 			}
+			q.checkVersion(version)
+		}
+	}
+}
+
+// Windows returns an iterator over all overlapping windows of k consecutive elements
+// in FIFO order: the elements [0, k), then [1, k+1), and so on. If the queue has fewer than
+// k elements, it yields nothing. The yielded slice is reused for the next window,
+// so do not retain it; use slices.Clone to keep a copy. If k is not positive, it panics.
+// Do not modify the queue while iterating; the iterator panics if it detects a modification.
+func (q *Queue[T]) Windows(k int) iter.Seq[[]T] {
+	if k <= 0 {
+		panic(fmt.Sprintf("queue: window size must be positive: k=%d", k))
+	}
+	return func(yield func([]T) bool) {
+		if q.length < k {
+			return
+		}
+		version := q.version
+		window := make([]T, k)
+		for i := range q.length - k + 1 {
+			q.copyOut(window, i)
+			if !yield(window) {
+				break
+			}
+			q.checkVersion(version)
 		}
 	}
 }
@@ -119,9 +333,536 @@ func (q *Queue[T]) At(i int) T {
 	return q.buffer[q.wrap(q.head+i)]
 }
 
+// InsertAt inserts an element at the specified index, shifting the following elements back.
+// The index must be in the range [0, Len()]; otherwise, it panics.
+func (q *Queue[T]) InsertAt(i int, x T) {
+	q.InsertMany(i, []T{x})
+}
+
+// InsertMany inserts multiple elements at the specified index, keeping their order.
+// It shifts whichever side of the queue is shorter, so the cost is O(min(i, Len()-i) + len(xs)).
+// The index must be in the range [0, Len()]; otherwise, it panics.
+func (q *Queue[T]) InsertMany(i int, xs []T) {
+	if i < 0 || i > q.Len() {
+		panic(fmt.Sprintf("queue: index out of range: i=%d, len=%d", i, q.Len()))
+	}
+	if len(xs) == 0 {
+		return
+	}
+
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("InsertMany")
+		defer q.guard.exit()
+	}
+
+	if q.remainingCapacity() < len(xs) {
+		q.reserve(q.length + len(xs))
+	}
+
+	k := len(xs)
+	if i < q.length-i {
+		q.copyWithin(-k, 0, i)
+		q.head = q.wrap(q.head - k)
+	} else {
+		q.copyWithin(i+k, i, q.length-i)
+	}
+	q.copyIn(i, xs)
+	q.length += k
+	q.added(k)
+}
+
+// RemoveAt removes and returns the element at the specified index.
+// It shifts whichever side of the queue is shorter, so the cost is O(min(i, Len()-i)).
+// If the index is out of range, it panics.
+func (q *Queue[T]) RemoveAt(i int) T {
+	if i < 0 || i >= q.Len() {
+		panic(fmt.Sprintf("queue: index out of range: i=%d, len=%d", i, q.Len()))
+	}
+
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("RemoveAt")
+		defer q.guard.exit()
+	}
+
+	x := q.buffer[q.wrap(q.head+i)]
+	if i < q.length-i-1 {
+		q.copyWithin(1, 0, i)
+		q.clearRange(0, 1)
+		q.head = q.wrap(q.head + 1)
+	} else {
+		q.copyWithin(i, i+1, q.length-i-1)
+		q.clearRange(q.length-1, 1)
+	}
+	q.length--
+	q.removed(1)
+	return x
+}
+
+// Delete removes the elements at the logical indexes [i, j), like slices.Delete.
+// It shifts whichever side of the queue is shorter and zeroes the vacated slots,
+// so the cost is O(j-i + min(i, Len()-j)).
+// If 0 <= i <= j <= Len() does not hold, it panics.
+func (q *Queue[T]) Delete(i, j int) {
+	if i < 0 || j < i || j > q.Len() {
+		panic(fmt.Sprintf("queue: range out of bounds: i=%d, j=%d, len=%d", i, j, q.Len()))
+	}
+	k := j - i
+	if k == 0 {
+		return
+	}
+
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("Delete")
+		defer q.guard.exit()
+	}
+
+	if i < q.length-j {
+		q.copyWithin(k, 0, i)
+		q.clearRange(0, k)
+		q.head = q.wrap(q.head + k)
+	} else {
+		q.copyWithin(i, j, q.length-j)
+		q.clearRange(q.length-k, k)
+	}
+	q.length -= k
+	q.removed(k)
+}
+
+// DeleteFunc removes all elements for which del returns true.
+// The remaining elements keep their relative order.
+func (q *Queue[T]) DeleteFunc(del func(T) bool) {
+	q.Keep(func(x T) bool { return !del(x) })
+}
+
+// Keep removes all elements for which keep returns false in a single compacting pass.
+// The remaining elements keep their relative order.
+func (q *Queue[T]) Keep(keep func(T) bool) {
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("Keep")
+		defer q.guard.exit()
+	}
+
+	w := 0
+	for r := range q.length {
+		x := q.buffer[q.wrap(q.head+r)]
+		if !keep(x) {
+			continue
+		}
+		if w != r {
+			q.buffer[q.wrap(q.head+w)] = x
+		}
+		w++
+	}
+	q.clearRange(w, q.length-w)
+	k := q.length - w
+	q.length = w
+	q.removed(k)
+}
+
+// CompactFunc replaces each run of consecutive elements for which eq returns true
+// with the first element of the run, like slices.CompactFunc, in a single compacting pass.
+// The vacated slots are zeroed.
+func (q *Queue[T]) CompactFunc(eq func(a, b T) bool) {
+	if q.length < 2 {
+		return
+	}
+
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("CompactFunc")
+		defer q.guard.exit()
+	}
+
+	// Like slices.CompactFunc, each element is compared with the element preceding it,
+	// not with the first element of the run.
+	w := 1
+	prev := q.buffer[q.head]
+	for r := 1; r < q.length; r++ {
+		x := q.buffer[q.wrap(q.head+r)]
+		if eq(x, prev) {
+			prev = x
+			continue
+		}
+		if w != r {
+			q.buffer[q.wrap(q.head+w)] = x
+		}
+		prev = x
+		w++
+	}
+	q.clearRange(w, q.length-w)
+	k := q.length - w
+	q.length = w
+	q.removed(k)
+}
+
+// Truncate keeps the first n elements and discards the rest.
+// If n >= Len(), the queue is not changed. If n is negative, it panics.
+func (q *Queue[T]) Truncate(n int) {
+	if n < 0 {
+		panic(fmt.Sprintf("queue: negative length: n=%d", n))
+	}
+	if n >= q.length {
+		return
+	}
+
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("Truncate")
+		defer q.guard.exit()
+	}
+
+	q.clearRange(n, q.length-n)
+	k := q.length - n
+	q.length = n
+	q.removed(k)
+}
+
+// SplitOff removes the first n elements from the queue and returns them as a new queue.
+// The elements are moved with bulk copies. If n is out of the range [0, Len()], it panics.
+func (q *Queue[T]) SplitOff(n int) *Queue[T] {
+	if n < 0 || n > q.Len() {
+		panic(fmt.Sprintf("queue: split index out of range: n=%d, len=%d", n, q.Len()))
+	}
+
+	front := &Queue[T]{}
+	if n == 0 {
+		return front
+	}
+
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("SplitOff")
+		defer q.guard.exit()
+	}
+
+	front.buffer = make([]T, ringbuf.CapacityFor(n))
+	front.length = q.copyOut(front.buffer[:n], 0)
+	q.discard(n)
+	return front
+}
+
+// CloneFunc returns a new queue containing copies of the elements made by copyElem, in FIFO order.
+// Use it to deep-copy queues of pointers or of values that contain slices or maps.
+// The statistics, hooks and allocator of q are not copied.
+func (q *Queue[T]) CloneFunc(copyElem func(T) T) *Queue[T] {
+	return Map(q, copyElem)
+}
+
+// ShrinkToFit reallocates the buffer to the smallest power of 2 that can hold the elements,
+// releasing the unused memory. If the buffer is already that small, it does nothing.
+func (q *Queue[T]) ShrinkToFit() {
+	newCapacity := ringbuf.CapacityFor(q.length)
+	if newCapacity >= len(q.buffer) {
+		return
+	}
+
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("ShrinkToFit")
+		defer q.guard.exit()
+	}
+
+	q.resize(newCapacity)
+}
+
+// Reset removes all elements and releases the buffer.
+// If the queue uses an allocator, the buffer is returned to it.
+func (q *Queue[T]) Reset() {
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("Reset")
+		defer q.guard.exit()
+	}
+
+	k := q.length
+	q.free(q.buffer)
+	q.head = 0
+	q.length = 0
+	q.buffer = nil
+	q.removed(k)
+	if q.ext != nil && q.ext.adaptive != nil {
+		q.ext.adaptive.reset()
+	}
+}
+
+// Rotate moves the first n elements to the back of the queue.
+// If n is negative, it moves the last -n elements to the front instead.
+// n is taken modulo Len(). When the buffer is full or has enough free space,
+// Rotate only adjusts the head and copies the shorter side.
+func (q *Queue[T]) Rotate(n int) {
+	if q.length == 0 {
+		return
+	}
+	n %= q.length
+	if n < 0 {
+		n += q.length
+	}
+	if n == 0 {
+		return
+	}
+
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("Rotate")
+		defer q.guard.exit()
+	}
+
+	// m is the number of elements moving to the front.
+	m := q.length - n
+	switch {
+	case q.length == len(q.buffer):
+		q.head = q.wrap(q.head + n)
+	case n <= m && n <= q.remainingCapacity():
+		q.copyWithin(q.length, 0, n)
+		q.clearRange(0, n)
+		q.head = q.wrap(q.head + n)
+	case m < n && m <= q.remainingCapacity():
+		q.copyWithin(-m, n, m)
+		q.clearRange(n, m)
+		q.head = q.wrap(q.head - m)
+	default:
+		q.reverse(0, n)
+		q.reverse(n, q.length)
+		q.reverse(0, q.length)
+	}
+}
+
+// Reverse reverses the order of the elements in the queue in place.
+func (q *Queue[T]) Reverse() {
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("Reverse")
+		defer q.guard.exit()
+	}
+
+	q.reverse(0, q.length)
+}
+
+// Shuffle permutes the elements uniformly at random in place, using rng as the source of randomness.
+func (q *Queue[T]) Shuffle(rng *rand.Rand) {
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("Shuffle")
+		defer q.guard.exit()
+	}
+
+	// Fisher-Yates shuffle over the logical indexes.
+	for i := q.length - 1; i > 0; i-- {
+		a, b := q.wrap(q.head+i), q.wrap(q.head+rng.Intn(i+1))
+		q.buffer[a], q.buffer[b] = q.buffer[b], q.buffer[a]
+	}
+}
+
+// MakeContiguous moves the elements so that they start at the beginning of the buffer
+// without wrapping around, and returns them as a single slice in FIFO order.
+// It does not allocate. The slice shares the buffer of the queue, so writes to it modify
+// the queue, and it is valid only until the queue is modified.
+func (q *Queue[T]) MakeContiguous() []T {
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("MakeContiguous")
+		defer q.guard.exit()
+	}
+
+	elements := q.linearize()
+	if q.head != 0 {
+		// The elements do not wrap around but do not start at the beginning either.
+		copy(q.buffer, elements)
+		clear(q.buffer[max(q.length, q.head) : q.head+q.length])
+		q.head = 0
+	}
+	return q.buffer[:q.length]
+}
+
+// Sample returns n elements chosen uniformly at random without replacement, in FIFO order,
+// without removing them. Every subset of n elements is equally likely.
+// It reads only the chosen elements, using O(n) time and memory regardless of Len().
+// If n >= Len(), it returns all elements. If n is negative, it panics.
+func (q *Queue[T]) Sample(rng *rand.Rand, n int) []T {
+	if n < 0 {
+		panic(fmt.Sprintf("queue: negative sample size: n=%d", n))
+	}
+	n = min(n, q.length)
+
+	// Choose n distinct indexes with Floyd's algorithm.
+	chosen := make(map[int]struct{}, n)
+	indexes := make([]int, 0, n)
+	for j := q.length - n; j < q.length; j++ {
+		i := rng.Intn(j + 1)
+		if _, ok := chosen[i]; ok {
+			i = j
+		}
+		chosen[i] = struct{}{}
+		indexes = append(indexes, i)
+	}
+	slices.Sort(indexes)
+
+	sample := make([]T, n)
+	for k, i := range indexes {
+		sample[k] = q.buffer[q.wrap(q.head+i)]
+	}
+	return sample
+}
+
+// IndexFunc returns the logical index of the first element satisfying f,
+// or -1 if none do.
+func (q *Queue[T]) IndexFunc(f func(T) bool) int {
+	for i := range q.length {
+		if f(q.buffer[q.wrap(q.head+i)]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ContainsFunc reports whether at least one element satisfies f.
+func (q *Queue[T]) ContainsFunc(f func(T) bool) bool {
+	return q.IndexFunc(f) >= 0
+}
+
+// CountFunc returns the number of elements satisfying f.
+func (q *Queue[T]) CountFunc(f func(T) bool) int {
+	n := 0
+	for i := range q.length {
+		if f(q.buffer[q.wrap(q.head+i)]) {
+			n++
+		}
+	}
+	return n
+}
+
+// MinFunc returns the minimal element in the queue as determined by cmp, and its logical index.
+// If there are multiple minimal elements, MinFunc returns the first one.
+// If the queue is empty, MinFunc returns the zero value of T and -1.
+func (q *Queue[T]) MinFunc(cmp func(a, b T) int) (T, int) {
+	return q.extremeFunc(func(a, b T) bool { return cmp(a, b) < 0 })
+}
+
+// MaxFunc returns the maximal element in the queue as determined by cmp, and its logical index.
+// If there are multiple maximal elements, MaxFunc returns the first one.
+// If the queue is empty, MaxFunc returns the zero value of T and -1.
+func (q *Queue[T]) MaxFunc(cmp func(a, b T) int) (T, int) {
+	return q.extremeFunc(func(a, b T) bool { return cmp(a, b) > 0 })
+}
+
+// SortFunc sorts the elements in the queue in ascending order as determined by cmp.
+// See slices.SortFunc for the requirements on cmp. The sort is not guaranteed to be stable.
+func (q *Queue[T]) SortFunc(cmp func(a, b T) int) {
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("SortFunc")
+		defer q.guard.exit()
+	}
+
+	slices.SortFunc(q.linearize(), cmp)
+}
+
+// IsSortedFunc reports whether the elements in the queue are sorted in ascending order
+// as determined by cmp.
+func (q *Queue[T]) IsSortedFunc(cmp func(a, b T) int) bool {
+	for i := 1; i < q.length; i++ {
+		if cmp(q.buffer[q.wrap(q.head+i)], q.buffer[q.wrap(q.head+i-1)]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// extremeFunc returns the first element that no other element is better than, and its logical index.
+func (q *Queue[T]) extremeFunc(better func(a, b T) bool) (T, int) {
+	if q.IsEmpty() {
+		var zero T
+		return zero, -1
+	}
+
+	m, index := q.buffer[q.head], 0
+	for i := 1; i < q.length; i++ {
+		if x := q.buffer[q.wrap(q.head+i)]; better(x, m) {
+			m, index = x, i
+		}
+	}
+	return m, index
+}
+
+// CheckInvariants validates the internal invariants of the queue
+// and panics with a diagnostic message if any of them is violated.
+// When built with the queuedebug build tag, every operation that modifies the queue
+// calls CheckInvariants automatically before returning.
+func (q *Queue[T]) CheckInvariants() {
+	capacity := len(q.buffer)
+	var violation string
+	switch {
+	case capacity&(capacity-1) != 0:
+		violation = "len(buffer) is not a power of 2"
+	case capacity == 0 && q.head != 0:
+		violation = "head != 0 while the buffer is empty"
+	case capacity != 0 && (q.head < 0 || q.head >= capacity):
+		violation = "head is out of the buffer"
+	case q.length < 0 || q.length > capacity:
+		violation = "length is out of the range [0, len(buffer)]"
+	default:
+		return
+	}
+	panic(fmt.Sprintf("queue: invariant violated: %s: head=%d, length=%d, len(buffer)=%d",
+		violation, q.head, q.length, capacity))
+}
+
+// checkVersion panics if the queue has been modified since the iterator observed version.
+func (q *Queue[T]) checkVersion(version uint) {
+	if q.version != version {
+		panic("queue: queue modified during iteration")
+	}
+}
+
 // wrap converts an index to the corresponding index in the buffer.
 func (q *Queue[T]) wrap(i int) int {
-	return i & (len(q.buffer) - 1)
+	return ringbuf.Index(q.buffer, i)
 }
 
 // remainingCapacity returns the number of elements that the buffer can still accommodate.
@@ -129,29 +870,162 @@ func (q *Queue[T]) remainingCapacity() int {
 	return len(q.buffer) - q.length
 }
 
+// copyWithin copies n elements starting at logical index src to logical index dst.
+// Indexes are relative to head and may be negative. The ranges may overlap.
+func (q *Queue[T]) copyWithin(dst, src, n int) {
+	ringbuf.CopyWithin(q.buffer, q.head+dst, q.head+src, n)
+}
+
+// segments returns the elements of the queue as two slices of the buffer.
+// The elements are the concatenation of the first and the second slice in FIFO order.
+func (q *Queue[T]) segments() ([]T, []T) {
+	return ringbuf.Regions(q.buffer, q.head, q.length)
+}
+
+// popInto removes elements from the front of the queue into dst with bulk copies
+// and returns the number of elements removed.
+func (q *Queue[T]) popInto(dst []T) int {
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("popInto")
+		defer q.guard.exit()
+	}
+
+	n := q.copyOut(dst, 0)
+	q.discard(n)
+	return n
+}
+
+// consume removes the first n elements, which the caller has already read through segments.
+// Caller must guarantee that 0 <= n <= length.
+func (q *Queue[T]) consume(n int) {
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("consume")
+		defer q.guard.exit()
+	}
+
+	q.discard(n)
+}
+
+// extend adds n elements that the caller has already written into the slice
+// returned by tailSegment. Caller must guarantee that n <= len(tailSegment()).
+func (q *Queue[T]) extend(n int) {
+	q.version++
+	if debug {
+		defer q.CheckInvariants()
+	}
+	if raceGuard {
+		q.guard.enter("extend")
+		defer q.guard.exit()
+	}
+
+	q.length += n
+	q.added(n)
+}
+
+// discard removes the first n elements.
+// Caller must guarantee that 0 <= n <= length.
+func (q *Queue[T]) discard(n int) {
+	q.clearRange(0, n)
+	q.head = q.wrap(q.head + n)
+	q.length -= n
+	q.removed(n)
+}
+
+// tailSegment returns the contiguous free space of the buffer following the last element.
+func (q *Queue[T]) tailSegment() []T {
+	tail := q.wrap(q.head + q.length)
+	if tail < q.head || (tail == q.head && q.length != 0) {
+		return q.buffer[tail:q.head]
+	}
+	return q.buffer[tail:]
+}
+
+// copyIn copies xs into the buffer starting at logical index i.
+// Caller must guarantee that len(xs) <= len(buffer).
+func (q *Queue[T]) copyIn(i int, xs []T) {
+	ringbuf.CopyIn(q.buffer, q.head+i, xs)
+}
+
+// linearize rearranges the buffer so that the elements do not wrap around its end,
+// and returns the slice of the buffer holding them.
+func (q *Queue[T]) linearize() []T {
+	if q.head+q.length > len(q.buffer) {
+		ringbuf.RotateLeft(q.buffer, q.head)
+		q.head = 0
+	}
+	return q.buffer[q.head : q.head+q.length]
+}
+
+// reverse reverses the order of the elements in the logical range [i, j).
+func (q *Queue[T]) reverse(i, j int) {
+	for j--; i < j; i, j = i+1, j-1 {
+		a, b := q.wrap(q.head+i), q.wrap(q.head+j)
+		q.buffer[a], q.buffer[b] = q.buffer[b], q.buffer[a]
+	}
+}
+
+// copyOut copies elements starting at logical index i into dst
+// and returns the number of elements copied.
+func (q *Queue[T]) copyOut(dst []T, i int) int {
+	n := min(len(dst), q.length-i)
+	if n <= 0 {
+		return 0
+	}
+	ringbuf.CopyOut(dst[:n], q.buffer, q.head+i)
+	return n
+}
+
+// clearRange sets n elements starting at logical index i to the zero value
+// so that the buffer does not keep references to removed elements.
+func (q *Queue[T]) clearRange(i, n int) {
+	ringbuf.Clear(q.buffer, q.head+i, n)
+}
+
 // reserve ensures that the buffer has enough capacity to store requiredCapacity elements.
 // Caller must guarantee that requiredCapacity > len(buffer).
 func (q *Queue[T]) reserve(requiredCapacity int) {
-	newCapacity := bitCeil(uint(requiredCapacity))
-
-	capacity := len(q.buffer)
-	if capacity == 0 {
-		q.buffer = make([]T, newCapacity)
-		return
+	if q.ext != nil && q.ext.adaptive != nil {
+		requiredCapacity = max(requiredCapacity, q.ext.adaptive.hint)
 	}
+	q.resize(ringbuf.CapacityFor(requiredCapacity))
+}
 
-	newBuffer := make([]T, newCapacity)
-	head := q.wrap(q.head)
-	length := q.length
-	n := copy(newBuffer, q.buffer[head:min(head+length, capacity)])
-	copy(newBuffer[n:], q.buffer[:length-n])
+// resize reallocates the buffer with newCapacity and moves the elements to its beginning.
+// Caller must guarantee that newCapacity is a power of 2 or zero, and newCapacity >= length.
+func (q *Queue[T]) resize(newCapacity int) {
+	oldBuffer := q.buffer
+	oldCapacity := len(oldBuffer)
+	newBuffer := q.alloc(newCapacity)
+	q.copyOut(newBuffer, 0)
 
 	q.head = 0
 	q.buffer = newBuffer
-}
+	q.free(oldBuffer)
 
-// bitCeil returns the minimum power of 2 that is greater than or equal to x.
-// It returns 0 when x is 0.
-func bitCeil(x uint) uint {
-	return 1 << (bits.UintSize - bits.LeadingZeros(x-1))
+	e := q.ext
+	if e == nil {
+		return
+	}
+	if newCapacity > oldCapacity {
+		if e.stats != nil {
+			e.stats.Grows++
+		}
+		if e.onGrow != nil {
+			op, version := q.beginCallback()
+			e.onGrow(oldCapacity, newCapacity)
+			q.endCallback("OnGrow", op, version)
+		}
+	} else if e.onShrink != nil {
+		op, version := q.beginCallback()
+		e.onShrink(oldCapacity, newCapacity)
+		q.endCallback("OnShrink", op, version)
+	}
 }
@@ -1,12 +1,16 @@
 package queue_test
 
 import (
+	"cmp"
 	"fmt"
+	"iter"
 	"math/rand"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/nojima/queue-go"
+	"github.com/nojima/queue-go/queuetest"
 )
 
 func ExampleQueue() {
@@ -43,6 +47,442 @@ func ExampleQueue_All() {
 	// 4
 }
 
+func TestQueue_SwapContents(t *testing.T) {
+	// Setup
+	a := newQueue(5, 3, 1, 4)
+	b := newQueue(0, 1, 5, 9, 2, 6, 5, 3, 5, 8)
+	capA, capB := a.Cap(), b.Cap()
+
+	// Exercise
+	a.SwapContents(b)
+
+	// Verify
+	if actual, expected := slices.Collect(a.All()), []int{1, 5, 9, 2, 6, 5, 3, 5, 8}; !slices.Equal(actual, expected) {
+		t.Errorf("a: %v; want: %v", actual, expected)
+	}
+	if actual, expected := slices.Collect(b.All()), []int{3, 1, 4}; !slices.Equal(actual, expected) {
+		t.Errorf("b: %v; want: %v", actual, expected)
+	}
+	if a.Cap() != capB || b.Cap() != capA {
+		t.Errorf("Cap() = %v, %v; want %v, %v", a.Cap(), b.Cap(), capB, capA)
+	}
+
+	// Both queues must be usable as usual.
+	a.Push(7)
+	b.Push(7)
+	a.CheckInvariants()
+	b.CheckInvariants()
+}
+
+func TestQueue_SwapContents_allocators(t *testing.T) {
+	// Setup
+	var allocA, allocB trackingAllocator
+	var a, b queue.Queue[int]
+	a.UseAllocator(&allocA)
+	b.UseAllocator(&allocB)
+	a.PushMany([]int{3, 1, 4})
+	b.PushMany([]int{1, 5, 9, 2, 6})
+
+	// Exercise
+	a.SwapContents(&b)
+	a.Reset()
+	b.Reset()
+
+	// Verify: each buffer is released to the allocator it came from.
+	for _, alloc := range []*trackingAllocator{&allocA, &allocB} {
+		if !slices.Equal(alloc.frees, alloc.allocs) {
+			t.Errorf("allocs: %v, frees: %v; want the same buffers", alloc.allocs, alloc.frees)
+		}
+	}
+}
+
+func TestQueue_PushSorted(t *testing.T) {
+	type pair struct{ key, order int }
+	cmpKey := func(a, b pair) int { return cmp.Compare(a.key, b.key) }
+
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			// Setup
+			var q queue.Queue[pair]
+			for range offset {
+				q.Push(pair{})
+				q.Pop()
+			}
+			keys := []int{5, 3, 8, 3, 1, 9, 5, 5, 0, 7}
+
+			// Exercise & Verify
+			for order, key := range keys {
+				x := pair{key, order}
+				i := q.PushSorted(x, cmpKey)
+				if q.At(i) != x {
+					t.Errorf("PushSorted(%v) = %v but At(%v) = %v", x, i, i, q.At(i))
+				}
+			}
+
+			// Verify
+			expected := make([]pair, len(keys))
+			for order, key := range keys {
+				expected[order] = pair{key, order}
+			}
+			slices.SortStableFunc(expected, cmpKey)
+			if actual := slices.Collect(q.All()); !slices.Equal(actual, expected) {
+				t.Errorf("actual: %v; want: %v", actual, expected)
+			}
+		})
+	}
+}
+
+func TestQueue_PushQueue(t *testing.T) {
+	testCases := []struct {
+		title    string
+		elements []int
+		others   []int
+	}{
+		{
+			title:    "empty",
+			elements: []int{},
+			others:   []int{},
+		},
+		{
+			title:    "into empty",
+			elements: []int{},
+			others:   []int{3, 1, 4},
+		},
+		{
+			title:    "fit",
+			elements: []int{3, 1, 4},
+			others:   []int{1, 5, 9, 2, 6},
+		},
+		{
+			title:    "grow",
+			elements: []int{3, 1, 4, 1, 5},
+			others:   []int{9, 2, 6, 5, 3, 5},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("%s/offset=%d", tc.title, offset), func(t *testing.T) {
+				// Setup
+				q := newQueue(offset, tc.elements...)
+				other := newQueue(7-offset, tc.others...)
+
+				// Exercise
+				q.PushQueue(other)
+
+				// Verify
+				actual := slices.Collect(q.All())
+				expected := slices.Concat(tc.elements, tc.others)
+				if !slices.Equal(actual, expected) {
+					t.Errorf("actual: %v; want: %v", actual, expected)
+				}
+				if actual := slices.Collect(other.All()); !slices.Equal(actual, tc.others) {
+					t.Errorf("other: %v; want: %v", actual, tc.others)
+				}
+			})
+		}
+	}
+
+	t.Run("self", func(t *testing.T) {
+		q := newQueue(5, 3, 1, 4, 1, 5)
+
+		q.PushQueue(q)
+
+		actual := slices.Collect(q.All())
+		expected := []int{3, 1, 4, 1, 5, 3, 1, 4, 1, 5}
+		if !slices.Equal(actual, expected) {
+			t.Errorf("actual: %v; want: %v", actual, expected)
+		}
+	})
+}
+
+func TestQueue_CopyTo(t *testing.T) {
+	testCases := []struct {
+		title    string
+		elements []int
+		dst      []int
+	}{
+		{
+			title:    "empty to empty",
+			elements: []int{},
+			dst:      []int{},
+		},
+		{
+			title:    "into empty",
+			elements: []int{3, 1, 4, 1, 5},
+			dst:      []int{},
+		},
+		{
+			title:    "shorter",
+			elements: []int{3, 1},
+			dst:      []int{9, 2, 6, 5, 3, 5},
+		},
+		{
+			title:    "longer",
+			elements: []int{3, 1, 4, 1, 5, 9, 2, 6, 5, 3},
+			dst:      []int{8, 9},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("%s/offset=%d", tc.title, offset), func(t *testing.T) {
+				// Setup
+				q := newQueue(offset, tc.elements...)
+				dst := newQueue(7-offset, tc.dst...)
+				capacity := dst.Cap()
+
+				// Exercise
+				q.CopyTo(dst)
+
+				// Verify
+				if actual := slices.Collect(dst.All()); !slices.Equal(actual, tc.elements) {
+					t.Errorf("dst: %v; want: %v", actual, tc.elements)
+				}
+				if actual := slices.Collect(q.All()); !slices.Equal(actual, tc.elements) {
+					t.Errorf("q: %v; want: %v", actual, tc.elements)
+				}
+				if len(tc.elements) <= capacity && dst.Cap() != capacity {
+					t.Errorf("Cap() = %v; want the buffer of %v to be reused", dst.Cap(), capacity)
+				}
+			})
+		}
+	}
+}
+
+func TestQueue_PopIf(t *testing.T) {
+	testCases := []struct {
+		title      string
+		elements   []int
+		expectedX  int
+		expectedOK bool
+		rest       []int
+	}{
+		{
+			title:      "empty",
+			elements:   []int{},
+			expectedX:  0,
+			expectedOK: false,
+			rest:       []int{},
+		},
+		{
+			title:      "satisfied",
+			elements:   []int{4, 1, 5},
+			expectedX:  4,
+			expectedOK: true,
+			rest:       []int{1, 5},
+		},
+		{
+			title:      "not satisfied",
+			elements:   []int{3, 1, 4},
+			expectedX:  0,
+			expectedOK: false,
+			rest:       []int{3, 1, 4},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			q := newQueue(0, tc.elements...)
+
+			x, ok := q.PopIf(func(x int) bool { return x%2 == 0 })
+
+			if x != tc.expectedX || ok != tc.expectedOK {
+				t.Errorf("PopIf() = %v, %v; want %v, %v", x, ok, tc.expectedX, tc.expectedOK)
+			}
+			if actual := slices.Collect(q.All()); !slices.Equal(actual, tc.rest) {
+				t.Errorf("rest: %v; want: %v", actual, tc.rest)
+			}
+		})
+	}
+}
+
+func TestQueue_PopWhile(t *testing.T) {
+	isSmall := func(x int) bool { return x < 5 }
+
+	t.Run("all", func(t *testing.T) {
+		q := newQueue(5, 3, 1, 4, 1, 5, 9, 2)
+
+		popped := slices.Collect(q.PopWhile(isSmall))
+
+		if expected := []int{3, 1, 4, 1}; !slices.Equal(popped, expected) {
+			t.Errorf("popped: %v; want: %v", popped, expected)
+		}
+		if actual, expected := slices.Collect(q.All()), []int{5, 9, 2}; !slices.Equal(actual, expected) {
+			t.Errorf("rest: %v; want: %v", actual, expected)
+		}
+	})
+
+	t.Run("break", func(t *testing.T) {
+		q := newQueue(5, 3, 1, 4, 1, 5, 9, 2)
+
+		for x := range q.PopWhile(isSmall) {
+			if x == 4 {
+				break
+			}
+		}
+
+		if actual, expected := slices.Collect(q.All()), []int{1, 5, 9, 2}; !slices.Equal(actual, expected) {
+			t.Errorf("rest: %v; want: %v", actual, expected)
+		}
+	})
+}
+
+func TestQueue_EvictOlderThan(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts := func(sec int) time.Time { return base.Add(time.Duration(sec) * time.Second) }
+
+	testCases := []struct {
+		title    string
+		elements []int
+		cutoff   int
+		expected []int
+	}{
+		{
+			title:    "empty",
+			elements: []int{},
+			cutoff:   10,
+			expected: []int{},
+		},
+		{
+			title:    "none",
+			elements: []int{5, 6, 7},
+			cutoff:   5,
+			expected: []int{5, 6, 7},
+		},
+		{
+			title:    "some",
+			elements: []int{1, 2, 3, 4, 5, 6, 7},
+			cutoff:   4,
+			expected: []int{4, 5, 6, 7},
+		},
+		{
+			title:    "all",
+			elements: []int{1, 2, 3, 4, 5, 6, 7},
+			cutoff:   8,
+			expected: []int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("%s/offset=%d", tc.title, offset), func(t *testing.T) {
+				// Setup
+				q := newQueue(offset, tc.elements...)
+
+				// Exercise
+				n := q.EvictOlderThan(ts(tc.cutoff), ts)
+
+				// Verify
+				if n != len(tc.elements)-len(tc.expected) {
+					t.Errorf("EvictOlderThan() = %v; want %v", n, len(tc.elements)-len(tc.expected))
+				}
+				if actual := slices.Collect(q.All()); !slices.Equal(actual, tc.expected) {
+					t.Errorf("rest: %v; want: %v", actual, tc.expected)
+				}
+			})
+		}
+	}
+}
+
+func TestQueue_Windows(t *testing.T) {
+	testCases := []struct {
+		title    string
+		elements []int
+		k        int
+		expected [][]int
+	}{
+		{
+			title:    "shorter than k",
+			elements: []int{3, 1},
+			k:        3,
+			expected: nil,
+		},
+		{
+			title:    "exactly k",
+			elements: []int{3, 1, 4},
+			k:        3,
+			expected: [][]int{{3, 1, 4}},
+		},
+		{
+			title:    "many",
+			elements: []int{3, 1, 4, 1, 5, 9},
+			k:        3,
+			expected: [][]int{{3, 1, 4}, {1, 4, 1}, {4, 1, 5}, {1, 5, 9}},
+		},
+		{
+			title:    "k=1",
+			elements: []int{3, 1, 4},
+			k:        1,
+			expected: [][]int{{3}, {1}, {4}},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("%s/offset=%d", tc.title, offset), func(t *testing.T) {
+				// Setup
+				q := newQueue(offset, tc.elements...)
+
+				// Exercise
+				var actual [][]int
+				for w := range q.Windows(tc.k) {
+					actual = append(actual, slices.Clone(w))
+				}
+
+				// Verify
+				if !slices.EqualFunc(actual, tc.expected, slices.Equal) {
+					t.Errorf("actual: %v; want: %v", actual, tc.expected)
+				}
+			})
+		}
+	}
+}
+
+func TestQueue_All_modified(t *testing.T) {
+	testCases := []struct {
+		title  string
+		seq    func(q *queue.Queue[int]) iter.Seq[int]
+		modify func(q *queue.Queue[int])
+	}{
+		{
+			title:  "All/Push",
+			seq:    (*queue.Queue[int]).All,
+			modify: func(q *queue.Queue[int]) { q.Push(0) },
+		},
+		{
+			title:  "All/Pop",
+			seq:    (*queue.Queue[int]).All,
+			modify: func(q *queue.Queue[int]) { q.Pop() },
+		},
+		{
+			title:  "Backward/RemoveAt",
+			seq:    (*queue.Queue[int]).Backward,
+			modify: func(q *queue.Queue[int]) { q.RemoveAt(0) },
+		},
+		{
+			title:  "Backward/Reverse",
+			seq:    (*queue.Queue[int]).Backward,
+			modify: func(q *queue.Queue[int]) { q.Reverse() },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			q := newQueue(5, 3, 1, 4, 1, 5)
+
+			defer func() {
+				if recover() == nil {
+					t.Errorf("iterator did not panic on modification")
+				}
+			}()
+			for range tc.seq(q) {
+				tc.modify(q)
+			}
+		})
+	}
+}
+
 func TestQueue_Backward(t *testing.T) {
 	testCases := []struct {
 		title    string
@@ -102,72 +542,724 @@ func TestQueue_At(t *testing.T) {
 	}
 }
 
-func TestRandomized(t *testing.T) {
-	for k := 0; k < 1000; k++ {
-		var q queue.Queue[int]
-		var v []int
+func TestQueue_InsertMany(t *testing.T) {
+	testCases := []struct {
+		title    string
+		index    int
+		xs       []int
+		expected []int
+	}{
+		{
+			title:    "front",
+			index:    0,
+			xs:       []int{7, 8},
+			expected: []int{7, 8, 3, 1, 4, 1, 5},
+		},
+		{
+			title:    "front half",
+			index:    1,
+			xs:       []int{7, 8},
+			expected: []int{3, 7, 8, 1, 4, 1, 5},
+		},
+		{
+			title:    "back half",
+			index:    4,
+			xs:       []int{7, 8},
+			expected: []int{3, 1, 4, 1, 7, 8, 5},
+		},
+		{
+			title:    "back",
+			index:    5,
+			xs:       []int{7, 8},
+			expected: []int{3, 1, 4, 1, 5, 7, 8},
+		},
+		{
+			title:    "grow",
+			index:    2,
+			xs:       []int{7, 8, 9, 10},
+			expected: []int{3, 1, 7, 8, 9, 10, 4, 1, 5},
+		},
+		{
+			title:    "nothing",
+			index:    2,
+			xs:       nil,
+			expected: []int{3, 1, 4, 1, 5},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("%s/offset=%d", tc.title, offset), func(t *testing.T) {
+				// Setup
+				q := newQueue(offset, 3, 1, 4, 1, 5)
+
+				// Exercise
+				q.InsertMany(tc.index, tc.xs)
+
+				// Verify
+				actual := slices.Collect(q.All())
+				if !slices.Equal(actual, tc.expected) {
+					t.Errorf("actual: %v; want: %v", actual, tc.expected)
+				}
+			})
+		}
+	}
+}
+
+func TestQueue_Truncate(t *testing.T) {
+	for _, n := range []int{0, 3, 7, 10} {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("n=%d/offset=%d", n, offset), func(t *testing.T) {
+				// Setup
+				elements := []int{3, 1, 4, 1, 5, 9, 2}
+				q := newQueue(offset, elements...)
+
+				// Exercise
+				q.Truncate(n)
 
-		for i := 0; i < 1000; i++ {
-			r := rand.Uint32()
-			switch r % 3 {
-			case 0:
-				q.Push(i)
-				v = append(v, i)
-			case 1:
-				var xs []int
-				for range rand.Intn(10) {
-					xs = append(xs, rand.Int())
+				// Verify
+				actual := slices.Collect(q.All())
+				expected := elements[:min(n, len(elements))]
+				if !slices.Equal(actual, expected) {
+					t.Errorf("actual: %v; want: %v", actual, expected)
 				}
-				q.PushMany(xs)
-				v = append(v, xs...)
-			case 2:
-				x, ok := q.Pop()
+			})
+		}
+	}
+}
+
+func TestQueue_SplitOff(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 7} {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("n=%d/offset=%d", n, offset), func(t *testing.T) {
+				// Setup
+				elements := []int{3, 1, 4, 1, 5, 9, 2}
+				q := newQueue(offset, elements...)
 
-				var expectedX int
-				var expectedOK bool
-				if len(v) == 0 {
-					expectedX = 0
-					expectedOK = false
-				} else {
-					expectedX = v[0]
-					expectedOK = true
-					v = v[1:]
+				// Exercise
+				front := q.SplitOff(n)
+
+				// Verify
+				if actual := slices.Collect(front.All()); !slices.Equal(actual, elements[:n]) {
+					t.Errorf("front: %v; want: %v", actual, elements[:n])
+				}
+				if actual := slices.Collect(q.All()); !slices.Equal(actual, elements[n:]) {
+					t.Errorf("rest: %v; want: %v", actual, elements[n:])
 				}
 
-				if x != expectedX || ok != expectedOK {
-					t.Errorf("Pop() = %v, %v; want %v, %v", x, ok, expectedX, expectedOK)
+				// The new queue must be usable as usual.
+				front.Push(6)
+				if x := front.At(front.Len() - 1); x != 6 {
+					t.Errorf("At(%v) = %v; want %v", front.Len()-1, x, 6)
 				}
+			})
+		}
+	}
+}
+
+func TestQueue_CloneFunc(t *testing.T) {
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			// Setup
+			var q queue.Queue[[]int]
+			for range offset {
+				q.Push(nil)
+				q.Pop()
 			}
+			q.Push([]int{3, 1})
+			q.Push([]int{4})
+			q.Push([]int{1, 5, 9})
+
+			// Exercise
+			clone := q.CloneFunc(slices.Clone)
 
-			if q.Len() != len(v) {
-				t.Errorf("Len() = %v; want %v", q.Len(), len(v))
+			// Verify
+			clone.At(0)[0] = 100
+			clone.Push([]int{2})
+			if x := q.At(0)[0]; x != 3 {
+				t.Errorf("original element modified through clone: %v", x)
+			}
+			if q.Len() != 3 || clone.Len() != 4 {
+				t.Errorf("Len() = %v, %v; want 3, 4", q.Len(), clone.Len())
+			}
+			if !slices.Equal(clone.At(2), []int{1, 5, 9}) {
+				t.Errorf("clone.At(2) = %v; want %v", clone.At(2), []int{1, 5, 9})
 			}
+		})
+	}
+}
+
+func TestQueue_Reset(t *testing.T) {
+	q := newQueue(5, 3, 1, 4, 1, 5)
 
-			x, ok := q.Peek()
-			var expectedX int
-			var expectedOK bool
-			if len(v) == 0 {
-				expectedX = 0
-				expectedOK = false
-			} else {
-				expectedX = v[0]
-				expectedOK = true
+	q.Reset()
+
+	if q.Len() != 0 || q.Cap() != 0 {
+		t.Errorf("Len() = %v, Cap() = %v; want 0, 0", q.Len(), q.Cap())
+	}
+	q.Push(9)
+	if x, ok := q.Pop(); x != 9 || !ok {
+		t.Errorf("Pop() = %v, %v; want %v, %v", x, ok, 9, true)
+	}
+}
+
+func TestQueue_Rotate(t *testing.T) {
+	testCases := []struct {
+		title    string
+		elements []int
+		n        int
+	}{
+		{
+			title:    "empty",
+			elements: []int{},
+			n:        3,
+		},
+		{
+			title:    "zero",
+			elements: []int{3, 1, 4, 1, 5},
+			n:        0,
+		},
+		{
+			title:    "forward",
+			elements: []int{3, 1, 4, 1, 5},
+			n:        2,
+		},
+		{
+			title:    "backward",
+			elements: []int{3, 1, 4, 1, 5},
+			n:        -2,
+		},
+		{
+			title:    "modulo",
+			elements: []int{3, 1, 4, 1, 5},
+			n:        13,
+		},
+		{
+			title:    "little free space",
+			elements: []int{3, 1, 4, 1, 5, 9, 2},
+			n:        3,
+		},
+		{
+			title:    "full",
+			elements: []int{3, 1, 4, 1, 5, 9, 2, 6},
+			n:        3,
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("%s/offset=%d", tc.title, offset), func(t *testing.T) {
+				// Setup
+				q := newQueue(offset, tc.elements...)
+
+				// Exercise
+				q.Rotate(tc.n)
+
+				// Verify
+				actual := slices.Collect(q.All())
+				expected := slices.Clone(tc.elements)
+				if len(expected) > 0 {
+					k := (tc.n%len(expected) + len(expected)) % len(expected)
+					expected = append(expected[k:], expected[:k]...)
+				}
+				if !slices.Equal(actual, expected) {
+					t.Errorf("actual: %v; want: %v", actual, expected)
+				}
+			})
+		}
+	}
+}
+
+func TestQueue_Reverse(t *testing.T) {
+	testCases := []struct {
+		title    string
+		elements []int
+	}{
+		{
+			title:    "empty",
+			elements: []int{},
+		},
+		{
+			title:    "one element",
+			elements: []int{1},
+		},
+		{
+			title:    "even",
+			elements: []int{3, 1, 4, 1, 5, 9},
+		},
+		{
+			title:    "odd",
+			elements: []int{3, 1, 4, 1, 5, 9, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("%s/offset=%d", tc.title, offset), func(t *testing.T) {
+				// Setup
+				q := newQueue(offset, tc.elements...)
+
+				// Exercise
+				q.Reverse()
+
+				// Verify
+				actual := slices.Collect(q.All())
+				expected := slices.Clone(tc.elements)
+				slices.Reverse(expected)
+				if !slices.Equal(actual, expected) {
+					t.Errorf("actual: %v; want: %v", actual, expected)
+				}
+			})
+		}
+	}
+}
+
+func TestQueue_Shuffle(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			// Every element lands at every position with the same probability.
+			const trials = 6000
+			var counts [3][3]int
+			for range trials {
+				// Setup
+				q := newQueue(offset, 0, 1, 2)
+
+				// Exercise
+				q.Shuffle(rng)
+
+				// Verify
+				for i := range q.Len() {
+					counts[q.At(i)][i]++
+				}
+			}
+			for x := range counts {
+				for i, c := range counts[x] {
+					// The expected count is trials / 3 = 2000.
+					if c < 1800 || c > 2200 {
+						t.Errorf("element %v was at %v %v times; want about 2000", x, i, c)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestQueue_MakeContiguous(t *testing.T) {
+	for _, offset := range []int{0, 2, 5, 7} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			// Setup
+			elements := []int{3, 1, 4, 1, 5}
+			q := newQueue(offset, elements...)
+			capacity := q.Cap()
+
+			// Exercise
+			s := q.MakeContiguous()
+
+			// Verify
+			if !slices.Equal(s, elements) {
+				t.Errorf("MakeContiguous() = %v; want %v", s, elements)
+			}
+			if q.Cap() != capacity {
+				t.Errorf("Cap() = %v; want %v", q.Cap(), capacity)
 			}
-			if x != expectedX || ok != expectedOK {
-				t.Errorf("Peek() = %v, %v; want %v, %v", x, ok, expectedX, expectedOK)
+			s[0] = 9
+			if x := q.At(0); x != 9 {
+				t.Errorf("At(0) = %v; want the value written through the slice", x)
+			}
+			q.Push(2)
+			if actual, expected := slices.Collect(q.All()), []int{9, 1, 4, 1, 5, 2}; !slices.Equal(actual, expected) {
+				t.Errorf("actual: %v; want: %v", actual, expected)
 			}
+			q.CheckInvariants()
+		})
+	}
+}
+
+func TestQueue_Sample(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
 
-			if !q.IsEmpty() {
-				x := q.At(q.Len() - 1)
-				expectedX := v[len(v)-1]
-				if x != expectedX {
-					t.Errorf("At(%v) = %v; want %v", q.Len()-1, x, expectedX)
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			q := newQueue(offset, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+
+			// Every element is chosen with the same probability.
+			counts := make([]int, q.Len())
+			const trials = 10000
+			for range trials {
+				sample := q.Sample(rng, 3)
+				if len(sample) != 3 || !slices.IsSorted(sample) || len(slices.Compact(slices.Clone(sample))) != 3 {
+					t.Fatalf("Sample() = %v; want 3 distinct elements in FIFO order", sample)
+				}
+				for _, x := range sample {
+					counts[x]++
+				}
+			}
+			for x, c := range counts {
+				// The expected count is trials * 3/10 = 3000.
+				if c < 2700 || c > 3300 {
+					t.Errorf("element %v was sampled %v times; want about 3000", x, c)
 				}
 			}
+
+			if sample := q.Sample(rng, 20); !slices.Equal(sample, slices.Collect(q.All())) {
+				t.Errorf("Sample(20) = %v; want all elements", sample)
+			}
+			if q.Len() != 10 {
+				t.Errorf("Len() = %v after Sample; want 10", q.Len())
+			}
+		})
+	}
+}
+
+func TestQueue_IndexFunc(t *testing.T) {
+	type job struct {
+		id   int
+		tags []string
+	}
+	var jobs queue.Queue[job]
+	for range 5 {
+		jobs.Push(job{})
+		jobs.Pop()
+	}
+	jobs.PushMany([]job{{id: 1}, {id: 2, tags: []string{"urgent"}}, {id: 3}, {id: 4, tags: []string{"urgent"}}})
+
+	isUrgent := func(j job) bool { return slices.Contains(j.tags, "urgent") }
+	if i := jobs.IndexFunc(isUrgent); i != 1 {
+		t.Errorf("IndexFunc() = %v; want %v", i, 1)
+	}
+	if !jobs.ContainsFunc(isUrgent) {
+		t.Errorf("ContainsFunc() = false; want true")
+	}
+
+	isMissing := func(j job) bool { return j.id == 5 }
+	if i := jobs.IndexFunc(isMissing); i != -1 {
+		t.Errorf("IndexFunc() = %v; want %v", i, -1)
+	}
+	if jobs.ContainsFunc(isMissing) {
+		t.Errorf("ContainsFunc() = true; want false")
+	}
+
+	var empty queue.Queue[job]
+	if i := empty.IndexFunc(func(job) bool { return true }); i != -1 {
+		t.Errorf("IndexFunc() on empty queue = %v; want %v", i, -1)
+	}
+}
+
+func TestQueue_CountFunc(t *testing.T) {
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			q := newQueue(offset, 3, 1, 4, 1, 5, 9, 2)
+
+			actual := q.CountFunc(func(x int) bool { return x%2 == 1 })
+			if actual != 5 {
+				t.Errorf("CountFunc() = %v; want %v", actual, 5)
+			}
+		})
+	}
+}
+
+func TestQueue_MinFuncMaxFunc(t *testing.T) {
+	testCases := []struct {
+		title       string
+		elements    []int
+		expectedMin int
+		minIndex    int
+		expectedMax int
+		maxIndex    int
+	}{
+		{
+			title:       "empty",
+			elements:    []int{},
+			expectedMin: 0,
+			minIndex:    -1,
+			expectedMax: 0,
+			maxIndex:    -1,
+		},
+		{
+			title:       "one element",
+			elements:    []int{4},
+			expectedMin: 4,
+			minIndex:    0,
+			expectedMax: 4,
+			maxIndex:    0,
+		},
+		{
+			title:       "multiple elements",
+			elements:    []int{3, 1, 4, 1, 5, 9, 2, 9},
+			expectedMin: 1,
+			minIndex:    1,
+			expectedMax: 9,
+			maxIndex:    5,
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("%s/offset=%d", tc.title, offset), func(t *testing.T) {
+				q := newQueue(offset, tc.elements...)
+
+				x, i := q.MinFunc(cmp.Compare[int])
+				if x != tc.expectedMin || i != tc.minIndex {
+					t.Errorf("MinFunc() = %v, %v; want %v, %v", x, i, tc.expectedMin, tc.minIndex)
+				}
+				x, i = q.MaxFunc(cmp.Compare[int])
+				if x != tc.expectedMax || i != tc.maxIndex {
+					t.Errorf("MaxFunc() = %v, %v; want %v, %v", x, i, tc.expectedMax, tc.maxIndex)
+				}
+			})
 		}
 	}
 }
 
+func TestQueue_SortFunc(t *testing.T) {
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			// Setup
+			q := newQueue(offset, 3, 1, 4, 1, 5, 9, 2)
+
+			// Exercise
+			q.SortFunc(cmp.Compare[int])
+
+			// Verify
+			actual := slices.Collect(q.All())
+			expected := []int{1, 1, 2, 3, 4, 5, 9}
+			if !slices.Equal(actual, expected) {
+				t.Errorf("actual: %v; want: %v", actual, expected)
+			}
+		})
+	}
+}
+
+func TestQueue_IsSortedFunc(t *testing.T) {
+	testCases := []struct {
+		title    string
+		elements []int
+		expected bool
+	}{
+		{
+			title:    "empty",
+			elements: []int{},
+			expected: true,
+		},
+		{
+			title:    "sorted",
+			elements: []int{1, 1, 2, 3, 5, 8, 9},
+			expected: true,
+		},
+		{
+			title:    "not sorted",
+			elements: []int{1, 1, 2, 3, 5, 9, 8},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("%s/offset=%d", tc.title, offset), func(t *testing.T) {
+				q := newQueue(offset, tc.elements...)
+				actual := q.IsSortedFunc(cmp.Compare[int])
+				if actual != tc.expected {
+					t.Errorf("IsSortedFunc() = %v; want %v", actual, tc.expected)
+				}
+			})
+		}
+	}
+}
+
+func TestQueue_RemoveAt(t *testing.T) {
+	testCases := []struct {
+		title    string
+		index    int
+		expected []int
+	}{
+		{
+			title:    "first",
+			index:    0,
+			expected: []int{1, 4, 1, 5, 9, 2},
+		},
+		{
+			title:    "front half",
+			index:    2,
+			expected: []int{3, 1, 1, 5, 9, 2},
+		},
+		{
+			title:    "back half",
+			index:    5,
+			expected: []int{3, 1, 4, 1, 5, 2},
+		},
+		{
+			title:    "last",
+			index:    6,
+			expected: []int{3, 1, 4, 1, 5, 9},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("%s/offset=%d", tc.title, offset), func(t *testing.T) {
+				// Setup
+				q := newQueue(offset, 3, 1, 4, 1, 5, 9, 2)
+				want := q.At(tc.index)
+
+				// Exercise
+				x := q.RemoveAt(tc.index)
+
+				// Verify
+				if x != want {
+					t.Errorf("RemoveAt(%v) = %v; want %v", tc.index, x, want)
+				}
+				actual := slices.Collect(q.All())
+				if !slices.Equal(actual, tc.expected) {
+					t.Errorf("actual: %v; want: %v", actual, tc.expected)
+				}
+			})
+		}
+	}
+}
+
+func TestQueue_Delete(t *testing.T) {
+	elements := []int{3, 1, 4, 1, 5, 9, 2}
+	for i := 0; i <= len(elements); i++ {
+		for j := i; j <= len(elements); j++ {
+			for _, offset := range []int{0, 5} {
+				t.Run(fmt.Sprintf("[%d,%d)/offset=%d", i, j, offset), func(t *testing.T) {
+					// Setup
+					q := newQueue(offset, elements...)
+
+					// Exercise
+					q.Delete(i, j)
+
+					// Verify
+					expected := slices.Delete(slices.Clone(elements), i, j)
+					if actual := slices.Collect(q.All()); !slices.Equal(actual, expected) {
+						t.Errorf("actual: %v; want: %v", actual, expected)
+					}
+					q.CheckInvariants()
+				})
+			}
+		}
+	}
+
+	t.Run("out of range", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Delete(2, 8) did not panic")
+			}
+		}()
+		q := newQueue(0, elements...)
+		q.Delete(2, 8)
+	})
+}
+
+func TestQueue_CompactFunc(t *testing.T) {
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			// Setup
+			q := newQueue(offset, 10, 12, 21, 35, 31, 33, 40)
+
+			// Exercise
+			q.CompactFunc(func(a, b int) bool { return a/10 == b/10 })
+
+			// Verify
+			if actual, expected := slices.Collect(q.All()), []int{10, 21, 35, 40}; !slices.Equal(actual, expected) {
+				t.Errorf("actual: %v; want: %v", actual, expected)
+			}
+		})
+	}
+}
+
+func TestQueue_CompactFunc_adjacent(t *testing.T) {
+	// Setup: eq is not transitive, so the result depends on which elements are compared.
+	elements := []int{1, 2, 3, 4, 10, 11}
+	eq := func(a, b int) bool { return a-b == 1 || b-a == 1 }
+	q := newQueue(5, elements...)
+
+	// Exercise
+	q.CompactFunc(eq)
+
+	// Verify
+	actual := slices.Collect(q.All())
+	expected := slices.CompactFunc(slices.Clone(elements), eq)
+	if !slices.Equal(actual, expected) {
+		t.Errorf("actual: %v; want: %v", actual, expected)
+	}
+}
+
+func TestQueue_DeleteFunc(t *testing.T) {
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			// Setup
+			q := newQueue(offset, 3, 1, 4, 1, 5, 9, 2)
+
+			// Exercise
+			q.DeleteFunc(func(x int) bool { return x%2 == 1 })
+
+			// Verify
+			actual := slices.Collect(q.All())
+			expected := []int{4, 2}
+			if !slices.Equal(actual, expected) {
+				t.Errorf("actual: %v; want: %v", actual, expected)
+			}
+		})
+	}
+}
+
+func TestQueue_Keep(t *testing.T) {
+	testCases := []struct {
+		title    string
+		elements []int
+		expected []int
+	}{
+		{
+			title:    "empty",
+			elements: []int{},
+			expected: []int{},
+		},
+		{
+			title:    "keep all",
+			elements: []int{4, 2, 6},
+			expected: []int{4, 2, 6},
+		},
+		{
+			title:    "keep none",
+			elements: []int{3, 1, 5},
+			expected: []int{},
+		},
+		{
+			title:    "keep some",
+			elements: []int{3, 1, 4, 1, 5, 9, 2, 6},
+			expected: []int{4, 2, 6},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("%s/offset=%d", tc.title, offset), func(t *testing.T) {
+				// Setup
+				q := newQueue(offset, tc.elements...)
+
+				// Exercise
+				q.Keep(func(x int) bool { return x%2 == 0 })
+
+				// Verify
+				actual := slices.Collect(q.All())
+				if !slices.Equal(actual, tc.expected) {
+					t.Errorf("actual: %v; want: %v", actual, tc.expected)
+				}
+				if q.Len() != len(tc.expected) {
+					t.Errorf("Len() = %v; want %v", q.Len(), len(tc.expected))
+				}
+			})
+		}
+	}
+}
+
+func TestRandomized(t *testing.T) {
+	queuetest.Run(t, 1000, func() queue.FIFO[int] { return &queue.Queue[int]{} }, (*rand.Rand).Int)
+}
+
 func BenchmarkPushPop(b *testing.B) {
 	var q queue.Queue[int]
 	for i := 0; i < b.N; i++ {
@@ -185,3 +1277,29 @@ func BenchmarkPushPop(b *testing.B) {
 		expected++
 	}
 }
+
+func BenchmarkPushMany(b *testing.B) {
+	// The chunk size is not a divisor of the capacity, so the copies keep wrapping around.
+	q := newQueue(0, make([]int, 16)...)
+	chunk := make([]int, 48)
+	for i := 0; i < b.N; i++ {
+		q.PushMany(chunk)
+		q.Delete(0, len(chunk))
+	}
+}
+
+// newQueue returns a queue containing elements whose head is moved forward by offset slots,
+// so that the elements wrap around the end of the buffer when offset is large enough.
+func newQueue(offset int, elements ...int) *queue.Queue[int] {
+	var q queue.Queue[int]
+	for range offset {
+		q.Push(0)
+	}
+	for range offset {
+		q.Pop()
+	}
+	for _, x := range elements {
+		q.Push(x)
+	}
+	return &q
+}
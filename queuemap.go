@@ -0,0 +1,152 @@
+package queue
+
+import "iter"
+
+const (
+	// maxFreeQueues is the maximum number of emptied queues a QueueMap keeps for reuse.
+	maxFreeQueues = 64
+
+	// maxFreeQueueCap is the maximum capacity of the buffer an emptied queue keeps.
+	// Larger buffers are released so that a burst does not pin its memory forever.
+	maxFreeQueueCap = 64
+)
+
+// QueueMap manages a FIFO queue per key, such as a queue per session or per connection.
+// Only keys with at least one element are kept in the map. When a queue becomes empty,
+// it is removed from the map and a limited number of them are kept for reuse by other keys,
+// so that small buffers do not have to be allocated again.
+// The zero value for QueueMap is an empty map ready to use.
+// QueueMap is NOT safe for concurrent use.
+type QueueMap[K comparable, T any] struct {
+	// The non-empty queues by key.
+	queues map[K]*Queue[T]
+
+	// The emptied queues kept for reuse.
+	free []*Queue[T]
+
+	// The total number of elements in all queues.
+	length int
+}
+
+// Len returns the total number of elements in all queues.
+func (m *QueueMap[K, T]) Len() int {
+	return m.length
+}
+
+// NumKeys returns the number of keys that have at least one element.
+func (m *QueueMap[K, T]) NumKeys() int {
+	return len(m.queues)
+}
+
+// LenOf returns the number of elements for the key.
+func (m *QueueMap[K, T]) LenOf(key K) int {
+	if q, ok := m.queues[key]; ok {
+		return q.Len()
+	}
+	return 0
+}
+
+// PushTo adds an element to the back of the queue for the key.
+func (m *QueueMap[K, T]) PushTo(key K, x T) {
+	q, ok := m.queues[key]
+	if !ok {
+		if m.queues == nil {
+			m.queues = make(map[K]*Queue[T])
+		}
+		q = m.allocQueue()
+		m.queues[key] = q
+	}
+	q.Push(x)
+	m.length++
+}
+
+// PopFrom removes and returns the element at the front of the queue for the key.
+// If there is no element for the key, PopFrom returns the zero value of T and false.
+func (m *QueueMap[K, T]) PopFrom(key K) (T, bool) {
+	q, ok := m.queues[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+
+	x, _ := q.Pop()
+	m.length--
+	if q.IsEmpty() {
+		delete(m.queues, key)
+		m.freeQueue(q)
+	}
+	return x, true
+}
+
+// PeekAt returns the element at the front of the queue for the key without removing it.
+// If there is no element for the key, PeekAt returns the zero value of T and false.
+func (m *QueueMap[K, T]) PeekAt(key K) (T, bool) {
+	if q, ok := m.queues[key]; ok {
+		return q.Peek()
+	}
+	var zero T
+	return zero, false
+}
+
+// Delete removes all elements for the key.
+func (m *QueueMap[K, T]) Delete(key K) {
+	q, ok := m.queues[key]
+	if !ok {
+		return
+	}
+
+	m.length -= q.Len()
+	delete(m.queues, key)
+	q.Truncate(0)
+	m.freeQueue(q)
+}
+
+// Keys returns an iterator over the keys that have at least one element, in unspecified order.
+// Do not modify the map while iterating.
+func (m *QueueMap[K, T]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range m.queues {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the keys that have at least one element and their elements
+// in FIFO order. The keys are visited in unspecified order.
+// Do not modify the map while iterating.
+func (m *QueueMap[K, T]) All() iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		for key, q := range m.queues {
+			for x := range q.All() {
+				if !yield(key, x) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// allocQueue returns an empty queue, reusing an emptied one if available.
+func (m *QueueMap[K, T]) allocQueue() *Queue[T] {
+	if n := len(m.free); n > 0 {
+		q := m.free[n-1]
+		m.free[n-1] = nil
+		m.free = m.free[:n-1]
+		return q
+	}
+	return &Queue[T]{}
+}
+
+// freeQueue keeps an emptied queue for reuse unless enough queues are kept already.
+// A buffer larger than maxFreeQueueCap is released before the queue is kept.
+func (m *QueueMap[K, T]) freeQueue(q *Queue[T]) {
+	if len(m.free) >= maxFreeQueues {
+		return
+	}
+	if q.Cap() > maxFreeQueueCap {
+		q.Reset()
+	}
+	m.free = append(m.free, q)
+}
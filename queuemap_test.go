@@ -0,0 +1,89 @@
+package queue_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestQueueMap(t *testing.T) {
+	var m queue.QueueMap[string, int]
+	m.PushTo("alice", 1)
+	m.PushTo("bob", 2)
+	m.PushTo("alice", 3)
+	m.PushTo("carol", 4)
+
+	if m.Len() != 4 || m.NumKeys() != 3 || m.LenOf("alice") != 2 {
+		t.Errorf("Len() = %v, NumKeys() = %v, LenOf(alice) = %v; want 4, 3, 2",
+			m.Len(), m.NumKeys(), m.LenOf("alice"))
+	}
+
+	if x, ok := m.PeekAt("alice"); x != 1 || !ok {
+		t.Errorf("PeekAt(alice) = %v, %v; want %v, %v", x, ok, 1, true)
+	}
+	if x, ok := m.PopFrom("alice"); x != 1 || !ok {
+		t.Errorf("PopFrom(alice) = %v, %v; want %v, %v", x, ok, 1, true)
+	}
+	if x, ok := m.PopFrom("bob"); x != 2 || !ok {
+		t.Errorf("PopFrom(bob) = %v, %v; want %v, %v", x, ok, 2, true)
+	}
+	if x, ok := m.PopFrom("bob"); x != 0 || ok {
+		t.Errorf("PopFrom(bob) = %v, %v; want %v, %v", x, ok, 0, false)
+	}
+	m.Delete("carol")
+
+	keys := slices.Sorted(m.Keys())
+	if !slices.Equal(keys, []string{"alice"}) {
+		t.Errorf("Keys() = %v; want %v", keys, []string{"alice"})
+	}
+
+	// Reuse the emptied queues.
+	m.PushTo("dave", 5)
+	m.PushTo("erin", 6)
+	m.PushTo("frank", 7)
+
+	actual := maps.Collect(m.All())
+	expected := map[string]int{"alice": 3, "dave": 5, "erin": 6, "frank": 7}
+	if !maps.Equal(actual, expected) {
+		t.Errorf("All() = %v; want %v", actual, expected)
+	}
+	if m.Len() != 4 {
+		t.Errorf("Len() = %v; want %v", m.Len(), 4)
+	}
+}
+
+func TestQueueMap_reuseAfterBurst(t *testing.T) {
+	// Setup: a burst leaves more emptied queues than are kept, some with large buffers.
+	var m queue.QueueMap[int, int]
+	for key := range 200 {
+		for i := range key {
+			m.PushTo(key, i)
+		}
+	}
+	for key := range 200 {
+		for range key {
+			m.PopFrom(key)
+		}
+	}
+
+	// Exercise
+	for key := range 100 {
+		m.PushTo(key, key)
+		m.PushTo(key, key+1)
+	}
+
+	// Verify
+	if m.Len() != 200 || m.NumKeys() != 100 {
+		t.Fatalf("Len(), NumKeys() = %v, %v; want 200, 100", m.Len(), m.NumKeys())
+	}
+	for key := range 100 {
+		if x, ok := m.PopFrom(key); x != key || !ok {
+			t.Errorf("PopFrom(%v) = %v, %v; want %v, true", key, x, ok, key)
+		}
+		if m.LenOf(key) != 1 {
+			t.Errorf("LenOf(%v) = %v; want 1", key, m.LenOf(key))
+		}
+	}
+}
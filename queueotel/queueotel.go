@@ -0,0 +1,111 @@
+// Package queueotel provides a queue instrumented with OpenTelemetry.
+//
+// It records the depth of the queue and the time elements spend in it, and carries
+// the span context of the pushing side to the popping side so that the consumer span
+// can be linked to the producer span.
+//
+// It lives in a separate module so that the queue package itself does not depend on
+// OpenTelemetry.
+package queueotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/nojima/queue-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the meter used by Queue.
+const instrumentationName = "github.com/nojima/queue-go/queueotel"
+
+// Queue is a FIFO queue that records OpenTelemetry metrics:
+//
+//   - queue.depth: the number of elements in the queue (UpDownCounter)
+//   - queue.wait_time: the time elements spend in the queue in seconds (Histogram)
+//
+// Both metrics have the attribute queue.name.
+// Queue is NOT safe for concurrent use.
+type Queue[T any] struct {
+	queue queue.Queue[entry[T]]
+
+	depth metric.Int64UpDownCounter
+	wait  metric.Float64Histogram
+	attrs metric.MeasurementOption
+}
+
+// entry is an element with the information captured by Push.
+type entry[T any] struct {
+	value    T
+	enqueued time.Time
+	producer trace.SpanContext
+}
+
+// New returns an empty queue that records metrics with the meter provider.
+// name is used as the value of the queue.name attribute.
+func New[T any](name string, mp metric.MeterProvider) (*Queue[T], error) {
+	meter := mp.Meter(instrumentationName)
+	depth, err := meter.Int64UpDownCounter("queue.depth",
+		metric.WithDescription("The number of elements in the queue."),
+		metric.WithUnit("{element}"))
+	if err != nil {
+		return nil, err
+	}
+	wait, err := meter.Float64Histogram("queue.wait_time",
+		metric.WithDescription("The time elements spend in the queue."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	return &Queue[T]{
+		depth: depth,
+		wait:  wait,
+		attrs: metric.WithAttributeSet(attribute.NewSet(attribute.String("queue.name", name))),
+	}, nil
+}
+
+// Len returns the number of elements in the queue.
+func (q *Queue[T]) Len() int {
+	return q.queue.Len()
+}
+
+// IsEmpty returns true if the queue is empty.
+func (q *Queue[T]) IsEmpty() bool {
+	return q.queue.IsEmpty()
+}
+
+// Push adds an element to the back of the queue.
+// It remembers the time and the span context of ctx for the matching Pop.
+func (q *Queue[T]) Push(ctx context.Context, x T) {
+	q.queue.Push(entry[T]{
+		value:    x,
+		enqueued: time.Now(),
+		producer: trace.SpanContextFromContext(ctx),
+	})
+	q.depth.Add(ctx, 1, q.attrs)
+}
+
+// Pop removes and returns the element at the front of the queue,
+// together with a link to the span that was active when the element was pushed.
+// The link can be attached to the consumer span with trace.WithLinks;
+// its span context is invalid if there was no span.
+// If the queue is empty, Pop returns the zero value of T, an empty link and false.
+func (q *Queue[T]) Pop(ctx context.Context) (T, trace.Link, bool) {
+	e, ok := q.queue.Pop()
+	if !ok {
+		var zero T
+		return zero, trace.Link{}, false
+	}
+	q.depth.Add(ctx, -1, q.attrs)
+	q.wait.Record(ctx, time.Since(e.enqueued).Seconds(), q.attrs)
+	return e.value, trace.Link{SpanContext: e.producer}, true
+}
+
+// Peek returns the element at the front of the queue without removing it.
+// If the queue is empty, Peek returns the zero value of T and false.
+func (q *Queue[T]) Peek() (T, bool) {
+	e, ok := q.queue.Peek()
+	return e.value, ok
+}
@@ -0,0 +1,64 @@
+package queueotel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nojima/queue-go/queueotel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestQueue(t *testing.T) {
+	// Setup
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	q, err := queueotel.New[string]("jobs", mp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	producer := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), producer)
+
+	// Exercise
+	q.Push(ctx, "a")
+	q.Push(context.Background(), "b")
+	q.Push(context.Background(), "c")
+	x, link, ok := q.Pop(context.Background())
+
+	// Verify
+	if x != "a" || !ok {
+		t.Errorf("Pop() = %q, %v; want %q, %v", x, ok, "a", true)
+	}
+	if !link.SpanContext.Equal(producer) {
+		t.Errorf("link: %v; want %v", link.SpanContext, producer)
+	}
+	if _, link, _ := q.Pop(context.Background()); link.SpanContext.IsValid() {
+		t.Errorf("link of an element pushed without a span is valid: %v", link.SpanContext)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	depth, ok := metrics["queue.depth"].(metricdata.Sum[int64])
+	if !ok || len(depth.DataPoints) != 1 || depth.DataPoints[0].Value != 1 {
+		t.Errorf("queue.depth: %+v; want a single data point with value 1", metrics["queue.depth"])
+	}
+	wait, ok := metrics["queue.wait_time"].(metricdata.Histogram[float64])
+	if !ok || len(wait.DataPoints) != 1 || wait.DataPoints[0].Count != 2 {
+		t.Errorf("queue.wait_time: %+v; want a single data point with count 2", metrics["queue.wait_time"])
+	}
+}
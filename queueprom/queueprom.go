@@ -0,0 +1,129 @@
+// Package queueprom exposes metrics of queue.Queue as a Prometheus collector.
+//
+// It lives in a separate module so that the queue package itself does not depend on
+// the Prometheus client library.
+package queueprom
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nojima/queue-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector is a prometheus.Collector that reports metrics of one or more queues.
+// Each queue is identified by the "queue" label.
+// Use NewCollector to create a Collector and Register to add queues to it.
+type Collector struct {
+	length  *prometheus.Desc
+	cap     *prometheus.Desc
+	pushes  *prometheus.Desc
+	pops    *prometheus.Desc
+	grows   *prometheus.Desc
+	oldest  *prometheus.Desc
+	mu      sync.Mutex
+	sources []source
+}
+
+// source reads the metrics of a registered queue.
+type source struct {
+	name     string
+	snapshot func() snapshot
+}
+
+// snapshot is the metrics of a queue at some point.
+type snapshot struct {
+	length int
+	cap    int
+	stats  queue.Stats
+
+	// The age of the oldest element, or -1 if it is unknown.
+	oldestAge time.Duration
+}
+
+// NewCollector returns a collector whose metric names are prefixed by namespace.
+func NewCollector(namespace string) *Collector {
+	labels := []string{"queue"}
+	return &Collector{
+		length: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "length"),
+			"The number of elements in the queue.", labels, nil),
+		cap: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "capacity"),
+			"The number of elements the queue can hold without reallocating its buffer.", labels, nil),
+		pushes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "pushes_total"),
+			"The total number of elements added to the queue.", labels, nil),
+		pops: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "pops_total"),
+			"The total number of elements removed from the queue.", labels, nil),
+		grows: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "grows_total"),
+			"The number of times the buffer of the queue has been reallocated to grow.", labels, nil),
+		oldest: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "oldest_element_age_seconds"),
+			"The time since the element at the front of the queue was enqueued.", labels, nil),
+	}
+}
+
+// Register adds q to the collector under the given name.
+// The counters are zero unless EnableStats has been called on q.
+// If timestamp is not nil, it must return the time when an element was enqueued,
+// and the collector reports the age of the element at the front of the queue.
+//
+// Prometheus collects metrics from other goroutines, so mu must be the lock
+// that guards every access to q.
+func Register[T any](c *Collector, name string, q *queue.Queue[T], mu sync.Locker, timestamp func(T) time.Time) {
+	s := source{
+		name: name,
+		snapshot: func() snapshot {
+			mu.Lock()
+			defer mu.Unlock()
+
+			s := snapshot{
+				length:    q.Len(),
+				cap:       q.Cap(),
+				stats:     q.Stats(),
+				oldestAge: -1,
+			}
+			if x, ok := q.Peek(); ok && timestamp != nil {
+				s.oldestAge = time.Since(timestamp(x))
+			}
+			return s
+		},
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sources = append(c.sources, s)
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.length
+	ch <- c.cap
+	ch <- c.pushes
+	ch <- c.pops
+	ch <- c.grows
+	ch <- c.oldest
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	sources := c.sources
+	c.mu.Unlock()
+
+	for _, src := range sources {
+		s := src.snapshot()
+		ch <- prometheus.MustNewConstMetric(c.length, prometheus.GaugeValue, float64(s.length), src.name)
+		ch <- prometheus.MustNewConstMetric(c.cap, prometheus.GaugeValue, float64(s.cap), src.name)
+		ch <- prometheus.MustNewConstMetric(c.pushes, prometheus.CounterValue, float64(s.stats.Pushes), src.name)
+		ch <- prometheus.MustNewConstMetric(c.pops, prometheus.CounterValue, float64(s.stats.Pops), src.name)
+		ch <- prometheus.MustNewConstMetric(c.grows, prometheus.CounterValue, float64(s.stats.Grows), src.name)
+		if s.oldestAge >= 0 {
+			ch <- prometheus.MustNewConstMetric(c.oldest, prometheus.GaugeValue, s.oldestAge.Seconds(), src.name)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package queueprom_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nojima/queue-go"
+	"github.com/nojima/queue-go/queueprom"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	// Setup
+	var mu sync.Mutex
+	var jobs queue.Queue[int]
+	jobs.EnableStats()
+	var events queue.Queue[time.Time]
+
+	c := queueprom.NewCollector("test")
+	queueprom.Register(c, "jobs", &jobs, &mu, nil)
+	queueprom.Register(c, "events", &events, &mu, func(t time.Time) time.Time { return t })
+
+	// Exercise
+	mu.Lock()
+	jobs.PushMany([]int{3, 1, 4})
+	jobs.Pop()
+	events.Push(time.Now().Add(-time.Minute))
+	mu.Unlock()
+
+	// Verify
+	expected := `
+# HELP test_queue_capacity The number of elements the queue can hold without reallocating its buffer.
+# TYPE test_queue_capacity gauge
+test_queue_capacity{queue="events"} 1
+test_queue_capacity{queue="jobs"} 4
+# HELP test_queue_grows_total The number of times the buffer of the queue has been reallocated to grow.
+# TYPE test_queue_grows_total counter
+test_queue_grows_total{queue="events"} 0
+test_queue_grows_total{queue="jobs"} 1
+# HELP test_queue_length The number of elements in the queue.
+# TYPE test_queue_length gauge
+test_queue_length{queue="events"} 1
+test_queue_length{queue="jobs"} 2
+# HELP test_queue_pops_total The total number of elements removed from the queue.
+# TYPE test_queue_pops_total counter
+test_queue_pops_total{queue="events"} 0
+test_queue_pops_total{queue="jobs"} 1
+# HELP test_queue_pushes_total The total number of elements added to the queue.
+# TYPE test_queue_pushes_total counter
+test_queue_pushes_total{queue="events"} 0
+test_queue_pushes_total{queue="jobs"} 3
+`
+	err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"test_queue_length", "test_queue_capacity", "test_queue_pushes_total",
+		"test_queue_pops_total", "test_queue_grows_total")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if n := testutil.CollectAndCount(c, "test_queue_oldest_element_age_seconds"); n != 1 {
+		t.Errorf("the number of oldest_element_age_seconds metrics: %v; want 1", n)
+	}
+}
@@ -0,0 +1,144 @@
+// Package queuetest provides a model-based testing harness for FIFO queue implementations.
+//
+// Run applies long random sequences of operations to a queue and to a reference model
+// backed by a plain slice, and reports every divergence between them. It validates
+// third-party implementations such as synchronized, double-ended or bounded queues
+// against the same model that queue.Queue is tested with.
+// The model compares elements with ==, so the element type must be comparable.
+package queuetest
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+// The optional methods that Run also validates when the queue implements them.
+type (
+	pushManyer[T any] interface{ PushMany(xs []T) }
+	isEmptier         interface{ IsEmpty() bool }
+	atter[T any]      interface{ At(i int) T }
+	remover[T any]    interface{ RemoveAt(i int) T }
+	inserter[T any]   interface{ InsertAt(i int, x T) }
+
+	// invariantChecker is called after every operation so that the queue can verify
+	// its internal state, as queue.Queue does with CheckInvariants.
+	invariantChecker interface{ CheckInvariants() }
+)
+
+// The number of operations applied to each queue.
+const operations = 1000
+
+// Run validates rounds queues created by newQueue against a slice model, applying
+// a random sequence of operations to each and pushing elements generated by newElem.
+// newQueue must return a new empty queue each time it is called.
+// Besides the methods of queue.FIFO, Run validates PushMany, IsEmpty, At, RemoveAt and InsertAt
+// if the queue implements them with the same signatures as queue.Queue[T],
+// and calls CheckInvariants after every operation if the queue implements it.
+func Run[T comparable](t *testing.T, rounds int, newQueue func() queue.FIFO[T], newElem func(rng *rand.Rand) T) {
+	t.Helper()
+
+	seed := rand.Int63()
+	rng := rand.New(rand.NewSource(seed))
+
+	for k := range rounds {
+		if !runOnce(t, newQueue(), newElem, rng) {
+			t.Fatalf("queuetest: round %d failed (seed %d)", k, seed)
+		}
+	}
+}
+
+// runOnce applies random operations to q and the model, and reports whether they agreed.
+func runOnce[T comparable](t *testing.T, q queue.FIFO[T], newElem func(rng *rand.Rand) T, rng *rand.Rand) bool {
+	t.Helper()
+
+	var v []T
+	ok := true
+	errorf := func(format string, args ...any) {
+		t.Helper()
+		t.Errorf(format, args...)
+		ok = false
+	}
+
+	for i := 0; i < operations && ok; i++ {
+		switch rng.Intn(5) {
+		case 0:
+			x := newElem(rng)
+			q.Push(x)
+			v = append(v, x)
+		case 1:
+			xs := make([]T, rng.Intn(10))
+			for j := range xs {
+				xs[j] = newElem(rng)
+			}
+			if p, implemented := q.(pushManyer[T]); implemented {
+				p.PushMany(xs)
+			} else {
+				for _, x := range xs {
+					q.Push(x)
+				}
+			}
+			v = append(v, xs...)
+		case 2:
+			x, popped := q.Pop()
+			var expectedX T
+			expectedOK := len(v) != 0
+			if expectedOK {
+				expectedX = v[0]
+				v = v[1:]
+			}
+			if x != expectedX || popped != expectedOK {
+				errorf("Pop() = %v, %v; want %v, %v", x, popped, expectedX, expectedOK)
+			}
+		case 3:
+			r, implemented := q.(remover[T])
+			if !implemented || len(v) == 0 {
+				break
+			}
+			j := rng.Intn(len(v))
+			if x := r.RemoveAt(j); x != v[j] {
+				errorf("RemoveAt(%v) = %v; want %v", j, x, v[j])
+			}
+			v = slices.Delete(v, j, j+1)
+		case 4:
+			ins, implemented := q.(inserter[T])
+			if !implemented {
+				break
+			}
+			j := rng.Intn(len(v) + 1)
+			x := newElem(rng)
+			ins.InsertAt(j, x)
+			v = slices.Insert(v, j, x)
+		}
+
+		if c, implemented := q.(invariantChecker); implemented {
+			c.CheckInvariants()
+		}
+		if q.Len() != len(v) {
+			errorf("Len() = %v; want %v", q.Len(), len(v))
+		}
+		if e, implemented := q.(isEmptier); implemented && e.IsEmpty() != (len(v) == 0) {
+			errorf("IsEmpty() = %v; want %v", e.IsEmpty(), len(v) == 0)
+		}
+
+		x, peeked := q.Peek()
+		var expectedX T
+		expectedOK := len(v) != 0
+		if expectedOK {
+			expectedX = v[0]
+		}
+		if x != expectedX || peeked != expectedOK {
+			errorf("Peek() = %v, %v; want %v, %v", x, peeked, expectedX, expectedOK)
+		}
+
+		if a, implemented := q.(atter[T]); implemented && len(v) != 0 {
+			j := rng.Intn(len(v))
+			if x := a.At(j); x != v[j] {
+				errorf("At(%v) = %v; want %v", j, x, v[j])
+			}
+		}
+	}
+	return ok
+}
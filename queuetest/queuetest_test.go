@@ -0,0 +1,40 @@
+package queuetest_test
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/nojima/queue-go"
+	"github.com/nojima/queue-go/queuetest"
+)
+
+func TestRun(t *testing.T) {
+	queuetest.Run(t, 100, func() queue.FIFO[int] {
+		return &queue.Queue[int]{}
+	}, (*rand.Rand).Int)
+}
+
+// minimalQueue implements only the methods of queue.FIFO.
+type minimalQueue struct {
+	q queue.Queue[int]
+}
+
+func (m *minimalQueue) Push(x int)        { m.q.Push(x) }
+func (m *minimalQueue) Pop() (int, bool)  { return m.q.Pop() }
+func (m *minimalQueue) Peek() (int, bool) { return m.q.Peek() }
+func (m *minimalQueue) Len() int          { return m.q.Len() }
+
+func TestRun_minimal(t *testing.T) {
+	queuetest.Run(t, 100, func() queue.FIFO[int] {
+		return &minimalQueue{}
+	}, (*rand.Rand).Int)
+}
+
+func TestRun_string(t *testing.T) {
+	queuetest.Run(t, 100, func() queue.FIFO[string] {
+		return &queue.Queue[string]{}
+	}, func(rng *rand.Rand) string {
+		return strconv.Itoa(rng.Intn(100))
+	})
+}
@@ -0,0 +1,66 @@
+package queue
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+)
+
+// generator is the same interface as testing/quick.Generator.
+// It is redeclared here so that the package does not import testing/quick,
+// which registers command-line flags.
+type generator interface {
+	Generate(rand *rand.Rand, size int) reflect.Value
+}
+
+// Generate returns a random *Queue[T] for property-based testing.
+// It implements testing/quick.Generator, so that functions taking *Queue[T] can be
+// checked with quick.Check directly.
+//
+// The queue holds fewer than size elements, and its head is placed at a random position
+// so that the elements may wrap around the end of the buffer.
+// The elements are generated by the Generate method of T if T implements testing/quick.Generator.
+// Otherwise T must be a boolean, numeric or string type, or Generate panics.
+func (*Queue[T]) Generate(rand *rand.Rand, size int) reflect.Value {
+	q := &Queue[T]{}
+	if size <= 0 {
+		return reflect.ValueOf(q)
+	}
+
+	n := rand.Intn(size)
+	q.reserve(max(n, 1))
+	q.head = rand.Intn(len(q.buffer))
+	for range n {
+		q.Push(generateElement[T](rand, size))
+	}
+	return reflect.ValueOf(q)
+}
+
+// generateElement returns a random value of T.
+func generateElement[T any](rand *rand.Rand, size int) T {
+	var zero T
+	if g, ok := any(zero).(generator); ok {
+		return g.Generate(rand, size).Interface().(T)
+	}
+
+	v := reflect.New(reflect.TypeFor[T]()).Elem()
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(rand.Intn(2) == 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(rand.Int63() - rand.Int63())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(rand.Uint64())
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat((rand.Float64() - 0.5) * float64(size))
+	case reflect.String:
+		runes := make([]rune, rand.Intn(size+1))
+		for i := range runes {
+			runes[i] = rune(rand.Intn(0x10ffff))
+		}
+		v.SetString(string(runes))
+	default:
+		panic(fmt.Sprintf("queue: cannot generate a random value of %v; implement testing/quick.Generator on it", v.Type()))
+	}
+	return v.Interface().(T)
+}
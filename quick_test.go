@@ -0,0 +1,46 @@
+package queue_test
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+	"testing/quick"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestQueue_Generate(t *testing.T) {
+	// Reversing twice restores the original order.
+	property := func(q *queue.Queue[int]) bool {
+		before := slices.Collect(q.All())
+		q.Reverse()
+		q.Reverse()
+		return slices.Equal(slices.Collect(q.All()), before)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// point implements quick.Generator.
+type point struct{ x, y int }
+
+func (point) Generate(rand *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(point{rand.Intn(size), rand.Intn(size)})
+}
+
+func TestQueue_Generate_customElement(t *testing.T) {
+	property := func(q *queue.Queue[point]) bool {
+		q.CheckInvariants()
+		for p := range q.All() {
+			if p.x < 0 || p.y < 0 {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !queuerace
+
+package queue
+
+// raceGuard enables detection of overlapping calls of modifying operations.
+const raceGuard = false
+
+// guard records the modifying operation in progress on a queue.
+// It is empty unless the queuerace build tag is set.
+type guard struct{}
+
+func (g *guard) enter(op string) {}
+
+func (g *guard) exit() {}
+
+func (g *guard) suspend() *string { return nil }
+
+func (g *guard) resume(op *string) {}
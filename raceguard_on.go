@@ -0,0 +1,86 @@
+//go:build queuerace
+
+package queue
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// raceGuard enables detection of overlapping calls of modifying operations.
+const raceGuard = true
+
+// guard records the modifying operation in progress on a queue.
+// The name of the operation doubles as the in-use flag, so that entering and exiting
+// are a single compare-and-swap and store.
+// The guard is released while notification callbacks such as OnGrow run, but not while
+// predicates such as the one passed to Keep run, since the queue is then half-modified.
+type guard struct {
+	// The name of the operation in progress, or nil.
+	op atomic.Pointer[string]
+}
+
+// enter marks the operation op as in progress.
+// It panics if another operation is already in progress.
+func (g *guard) enter(op string) {
+	name := internOp(op)
+	if !g.op.CompareAndSwap(nil, name) {
+		panicOverlap(op, g.op.Load())
+	}
+}
+
+// exit marks the operation in progress as finished.
+func (g *guard) exit() {
+	g.op.Store(nil)
+}
+
+// suspend marks the operation in progress as finished while a user callback runs,
+// and returns it so that resume can restore it.
+func (g *guard) suspend() *string {
+	return g.op.Swap(nil)
+}
+
+// resume marks the operation returned by suspend as in progress again.
+// It panics if another operation has started in the meantime.
+func (g *guard) resume(op *string) {
+	if !g.op.CompareAndSwap(nil, op) {
+		panicOverlap(*op, g.op.Load())
+	}
+}
+
+func panicOverlap(op string, other *string) {
+	otherName := "another operation"
+	if other != nil {
+		otherName = *other
+	}
+	panic(fmt.Sprintf("queue: overlapping calls detected: %s called while %s is in progress "+
+		"(Queue is NOT safe for concurrent use)", op, otherName))
+}
+
+// The interned names of operations, so that enter does not allocate to store a name.
+var (
+	opNamesMu sync.RWMutex
+	opNames   = make(map[string]*string)
+)
+
+// internOp returns the canonical pointer to the name op.
+func internOp(op string) *string {
+	opNamesMu.RLock()
+	name, ok := opNames[op]
+	opNamesMu.RUnlock()
+	if ok {
+		return name
+	}
+
+	opNamesMu.Lock()
+	defer opNamesMu.Unlock()
+	if name, ok := opNames[op]; ok {
+		return name
+	}
+	// Copy op so that only this path moves a string to the heap.
+	interned := op
+	name = &interned
+	opNames[op] = name
+	return name
+}
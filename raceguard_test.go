@@ -0,0 +1,100 @@
+//go:build queuerace
+
+package queue_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestRaceGuard(t *testing.T) {
+	// Setup
+	q := newQueue(5, 3, 1, 4, 1, 5)
+
+	// Exercise
+	var recovered any
+	func() {
+		defer func() { recovered = recover() }()
+		q.Keep(func(x int) bool {
+			q.Push(x)
+			return true
+		})
+	}()
+
+	// Verify
+	msg := fmt.Sprint(recovered)
+	if !strings.Contains(msg, "Push called while Keep is in progress") {
+		t.Errorf("panic message = %q; want it to name Push and Keep", msg)
+	}
+
+	// The guard is released after the panic.
+	q.Push(9)
+}
+
+func TestRaceGuard_concurrent(t *testing.T) {
+	// Setup: one goroutine stays inside Keep until the other has called Push.
+	q := newQueue(5, 3, 1, 4)
+	inKeep := make(chan struct{})
+	pushed := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		q.Keep(func(x int) bool {
+			if x == 3 {
+				close(inKeep)
+				<-pushed
+			}
+			return true
+		})
+	}()
+	<-inKeep
+
+	// Exercise
+	var recovered any
+	func() {
+		defer func() { recovered = recover() }()
+		defer close(pushed)
+		q.Push(9)
+	}()
+	<-done
+
+	// Verify
+	msg := fmt.Sprint(recovered)
+	if !strings.Contains(msg, "Push called while Keep is in progress") {
+		t.Errorf("panic message = %q; want it to name Push and Keep", msg)
+	}
+}
+
+func TestRaceGuard_callback(t *testing.T) {
+	// Setup
+	var q queue.Queue[int]
+	var peeked int
+	q.OnGrow(func(oldCap, newCap int) {
+		// Reading the queue is allowed in a callback.
+		peeked, _ = q.Peek()
+		if newCap == 4 {
+			q.Push(100)
+		}
+	})
+	q.Push(1)
+	q.Push(2)
+
+	// Exercise
+	var recovered any
+	func() {
+		defer func() { recovered = recover() }()
+		q.Push(3)
+	}()
+
+	// Verify
+	if peeked != 1 {
+		t.Errorf("Peek() in the callback = %v; want 1", peeked)
+	}
+	msg := fmt.Sprint(recovered)
+	if !strings.Contains(msg, "modified by the OnGrow callback") {
+		t.Errorf("panic message = %q; want it to name the OnGrow callback", msg)
+	}
+}
@@ -0,0 +1,97 @@
+package queue
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimited is a consumer adapter that pops from a queue at most n elements per period.
+// It uses a token bucket that holds up to n tokens and is refilled continuously
+// at the rate of n tokens per period, so bursts of up to n elements are allowed.
+// RateLimited is NOT safe for concurrent use.
+type RateLimited[T any] struct {
+	queue *Queue[T]
+
+	// The capacity of the bucket and the time to refill one token.
+	burst    int
+	interval time.Duration
+
+	// The number of tokens in the bucket at last.
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimited returns an adapter that pops from q at most n elements per period.
+// The bucket is initially full. If n is not positive or period is not positive, it panics.
+func NewRateLimited[T any](q *Queue[T], n int, period time.Duration) *RateLimited[T] {
+	if n <= 0 || period <= 0 {
+		panic("queue: rate limit must be positive")
+	}
+	return &RateLimited[T]{
+		queue:    q,
+		burst:    n,
+		interval: period / time.Duration(n),
+		tokens:   float64(n),
+		last:     time.Now(),
+	}
+}
+
+// Pop removes and returns the element at the front of the queue if the rate limit allows it.
+// If the queue is empty or the rate limit is exceeded, Pop returns the zero value of T and false
+// without consuming a token. Delay tells how long to wait for the next token.
+func (r *RateLimited[T]) Pop() (T, bool) {
+	r.refill(time.Now())
+	if r.queue.IsEmpty() || r.tokens < 1 {
+		var zero T
+		return zero, false
+	}
+	r.tokens--
+	return r.queue.Pop()
+}
+
+// PopWait removes and returns the element at the front of the queue,
+// sleeping until the rate limit allows it. If the queue is empty, PopWait returns
+// the zero value of T and false immediately. If ctx is done while sleeping,
+// PopWait returns the zero value of T, false and the error of ctx.
+func (r *RateLimited[T]) PopWait(ctx context.Context) (T, bool, error) {
+	var zero T
+	if r.queue.IsEmpty() {
+		return zero, false, nil
+	}
+
+	for {
+		d := r.Delay()
+		if d <= 0 {
+			break
+		}
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, false, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	x, ok := r.Pop()
+	return x, ok, nil
+}
+
+// Delay returns how long to wait until the rate limit allows the next Pop.
+// It returns zero if a token is available now.
+func (r *RateLimited[T]) Delay() time.Duration {
+	r.refill(time.Now())
+	if r.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - r.tokens) * float64(r.interval))
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (r *RateLimited[T]) refill(now time.Time) {
+	elapsed := now.Sub(r.last)
+	if elapsed <= 0 {
+		return
+	}
+	r.tokens = min(float64(r.burst), r.tokens+float64(elapsed)/float64(r.interval))
+	r.last = now
+}
@@ -0,0 +1,60 @@
+package queue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestRateLimited(t *testing.T) {
+	var q queue.Queue[int]
+	q.PushMany([]int{3, 1, 4, 1, 5})
+	r := queue.NewRateLimited(&q, 2, time.Hour)
+
+	// The initial burst.
+	for _, expected := range []int{3, 1} {
+		if x, ok := r.Pop(); x != expected || !ok {
+			t.Errorf("Pop() = %v, %v; want %v, %v", x, ok, expected, true)
+		}
+	}
+
+	// The rate limit is exceeded.
+	if x, ok := r.Pop(); ok {
+		t.Errorf("Pop() = %v, %v; want rate limited", x, ok)
+	}
+	if d := r.Delay(); d <= 0 || d > 30*time.Minute {
+		t.Errorf("Delay() = %v; want (0, 30m]", d)
+	}
+	if q.Len() != 3 {
+		t.Errorf("Len() = %v; want %v", q.Len(), 3)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, ok, err := r.PopWait(ctx); ok || err != context.DeadlineExceeded {
+		t.Errorf("PopWait() = %v, %v; want false, %v", ok, err, context.DeadlineExceeded)
+	}
+}
+
+func TestRateLimited_PopWait(t *testing.T) {
+	var q queue.Queue[int]
+	q.PushMany([]int{3, 1, 4})
+	r := queue.NewRateLimited(&q, 1, 10*time.Millisecond)
+
+	start := time.Now()
+	for _, expected := range []int{3, 1, 4} {
+		x, ok, err := r.PopWait(context.Background())
+		if x != expected || !ok || err != nil {
+			t.Errorf("PopWait() = %v, %v, %v; want %v, %v, nil", x, ok, err, expected, true)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("3 elements were popped in %v; want >= 20ms", elapsed)
+	}
+
+	if _, ok, err := r.PopWait(context.Background()); ok || err != nil {
+		t.Errorf("PopWait() on empty queue = %v, %v; want false, nil", ok, err)
+	}
+}
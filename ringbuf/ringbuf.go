@@ -0,0 +1,214 @@
+// Package ringbuf provides a low-level circular buffer whose capacity is a power of 2.
+//
+// RingBuffer exposes the index arithmetic, growth and two-region copies of a circular buffer
+// without imposing a queue discipline, so that custom structures such as SPSC channels,
+// byte pipes and sliding windows can control the head and the tail directly.
+// queue.IncrementalQueue is built on it. queue.Queue tracks its head as an index rather
+// than a free-running position, so it uses the slice-level functions RingBuffer is built on,
+// such as Regions, CopyIn and CopyOut, and the capacity policy, CapacityFor.
+package ringbuf
+
+import "math/bits"
+
+// RingBuffer is a circular buffer whose capacity is a power of 2.
+//
+// The head and the tail are free-running counters: they only increase and wrap around
+// on overflow, and an element at position p is stored at index Wrap(p) of the buffer.
+// The elements of the buffer are those at positions [Head(), Tail()).
+//
+// The zero value for RingBuffer is an empty buffer with zero capacity ready to use.
+// RingBuffer is NOT safe for concurrent use.
+type RingBuffer[T any] struct {
+	// The position of the first element.
+	head uint
+
+	// The position following the last element.
+	// Invariant: tail - head <= len(buffer)
+	tail uint
+
+	// The storage of elements.
+	// Invariant: len(buffer) is a power of 2 or zero
+	buffer []T
+}
+
+// New returns an empty RingBuffer that can hold at least capacity elements.
+func New[T any](capacity int) *RingBuffer[T] {
+	var r RingBuffer[T]
+	r.Reserve(capacity)
+	return &r
+}
+
+// Len returns the number of elements in the buffer.
+func (r *RingBuffer[T]) Len() int {
+	return int(r.tail - r.head)
+}
+
+// Cap returns the capacity of the buffer, which is a power of 2 or zero.
+func (r *RingBuffer[T]) Cap() int {
+	return len(r.buffer)
+}
+
+// Free returns the number of elements that can be added without growing the buffer.
+func (r *RingBuffer[T]) Free() int {
+	return len(r.buffer) - r.Len()
+}
+
+// Head returns the position of the first element.
+func (r *RingBuffer[T]) Head() uint {
+	return r.head
+}
+
+// Tail returns the position following the last element.
+func (r *RingBuffer[T]) Tail() uint {
+	return r.tail
+}
+
+// Wrap converts a position to the corresponding index in the buffer.
+// Wrap must not be called when the capacity is zero.
+func (r *RingBuffer[T]) Wrap(p uint) int {
+	return Index(r.buffer, int(p))
+}
+
+// At returns the i-th element from the head.
+// It panics if i is out of range.
+func (r *RingBuffer[T]) At(i int) T {
+	r.checkIndex(i)
+	return r.buffer[r.Wrap(r.head+uint(i))]
+}
+
+// Set replaces the i-th element from the head with x.
+// It panics if i is out of range.
+func (r *RingBuffer[T]) Set(i int, x T) {
+	r.checkIndex(i)
+	r.buffer[r.Wrap(r.head+uint(i))] = x
+}
+
+// Push adds an element after the last element, growing the buffer if it is full.
+func (r *RingBuffer[T]) Push(x T) {
+	if r.Free() == 0 {
+		r.Reserve(len(r.buffer) + 1)
+	}
+	r.buffer[r.Wrap(r.tail)] = x
+	r.tail++
+}
+
+// Pop removes and returns the first element.
+// If the buffer is empty, Pop returns the zero value of T and false.
+func (r *RingBuffer[T]) Pop() (T, bool) {
+	if r.head == r.tail {
+		var zero T
+		return zero, false
+	}
+	p := r.Wrap(r.head)
+	x := r.buffer[p]
+	var zero T
+	r.buffer[p] = zero
+	r.head++
+	return x, true
+}
+
+// Peek returns the first element without removing it.
+// If the buffer is empty, Peek returns the zero value of T and false.
+func (r *RingBuffer[T]) Peek() (T, bool) {
+	if r.head == r.tail {
+		var zero T
+		return zero, false
+	}
+	return r.buffer[r.Wrap(r.head)], true
+}
+
+// AdvanceHead removes the first n elements, setting them to the zero value
+// so that the buffer does not keep references to them.
+// It panics if n is negative or greater than Len().
+func (r *RingBuffer[T]) AdvanceHead(n int) {
+	if n < 0 || n > r.Len() {
+		panic("ringbuf: AdvanceHead count out of range")
+	}
+	Clear(r.buffer, int(r.head), n)
+	r.head += uint(n)
+}
+
+// AdvanceTail adds n elements that the caller has already written into the slices
+// returned by Writable.
+// It panics if n is negative or greater than Free().
+func (r *RingBuffer[T]) AdvanceTail(n int) {
+	if n < 0 || n > r.Free() {
+		panic("ringbuf: AdvanceTail count out of range")
+	}
+	r.tail += uint(n)
+}
+
+// Readable returns the elements as two slices of the buffer.
+// The elements are the concatenation of the first and the second slice in order from the head.
+func (r *RingBuffer[T]) Readable() ([]T, []T) {
+	return Regions(r.buffer, int(r.head), r.Len())
+}
+
+// Writable returns the free space following the last element as two slices of the buffer.
+// Elements written to them become part of the buffer when AdvanceTail is called.
+func (r *RingBuffer[T]) Writable() ([]T, []T) {
+	return Regions(r.buffer, int(r.tail), r.Free())
+}
+
+// CopyOut copies elements starting at the i-th element from the head into dst
+// and returns the number of elements copied.
+// It panics if i is negative or greater than Len().
+func (r *RingBuffer[T]) CopyOut(dst []T, i int) int {
+	if i < 0 || i > r.Len() {
+		panic("ringbuf: CopyOut index out of range")
+	}
+	n := min(len(dst), r.Len()-i)
+	CopyOut(dst[:n], r.buffer, int(r.head+uint(i)))
+	return n
+}
+
+// CopyIn copies src into the buffer starting at the i-th position from the head,
+// overwriting elements or free space. It does not change the head or the tail.
+// It panics if the destination range exceeds the capacity.
+func (r *RingBuffer[T]) CopyIn(i int, src []T) {
+	if i < 0 || len(src) > len(r.buffer)-i {
+		panic("ringbuf: CopyIn range out of capacity")
+	}
+	CopyIn(r.buffer, int(r.head+uint(i)), src)
+}
+
+// Reserve grows the buffer, if necessary, so that it can hold at least n elements.
+// The capacity becomes the smallest power of 2 that is greater than or equal to n.
+// Positions of the elements do not change.
+func (r *RingBuffer[T]) Reserve(n int) {
+	if n <= len(r.buffer) {
+		return
+	}
+	old := *r
+	r.buffer = make([]T, CapacityFor(n))
+	a, b := old.Readable()
+	r.CopyIn(0, a)
+	r.CopyIn(len(a), b)
+}
+
+// Reset removes all elements and moves the head and the tail to position 0.
+// The capacity is kept.
+func (r *RingBuffer[T]) Reset() {
+	r.ResetAt(0)
+}
+
+// ResetAt removes all elements and moves the head and the tail to position p.
+// The capacity is kept.
+func (r *RingBuffer[T]) ResetAt(p uint) {
+	clear(r.buffer)
+	r.head = p
+	r.tail = p
+}
+
+// checkIndex panics if i is not a valid element index.
+func (r *RingBuffer[T]) checkIndex(i int) {
+	if i < 0 || i >= r.Len() {
+		panic("ringbuf: index out of range")
+	}
+}
+
+// CapacityFor returns the capacity of a buffer that holds n elements,
+// which is the minimum power of 2 that is greater than or equal to n, or 0 when n is 0.
+func CapacityFor(n int) int {
+	return 1 << (bits.UintSize - bits.LeadingZeros(uint(n)-1))
+}
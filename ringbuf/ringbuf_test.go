@@ -0,0 +1,183 @@
+package ringbuf_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go/ringbuf"
+)
+
+// contents returns the elements of r in order from the head.
+func contents[T any](r *ringbuf.RingBuffer[T]) []T {
+	a, b := r.Readable()
+	return append(slices.Clone(a), b...)
+}
+
+func TestRingBuffer_PushPop(t *testing.T) {
+	var r ringbuf.RingBuffer[int]
+	if _, ok := r.Pop(); ok {
+		t.Errorf("Pop() on empty buffer returned ok")
+	}
+
+	for round := range 3 {
+		for i := range 10 {
+			r.Push(i)
+		}
+		if r.Len() != 10 || r.Cap() != 16 {
+			t.Errorf("round %v: Len(), Cap() = %v, %v; want 10, 16", round, r.Len(), r.Cap())
+		}
+		for i := range 10 {
+			if x, ok := r.Pop(); x != i || !ok {
+				t.Errorf("round %v: Pop() = %v, %v; want %v, true", round, x, ok, i)
+			}
+		}
+	}
+	if r.Head() != 30 || r.Tail() != 30 {
+		t.Errorf("Head(), Tail() = %v, %v; want 30, 30", r.Head(), r.Tail())
+	}
+}
+
+func TestRingBuffer_Reserve(t *testing.T) {
+	// Setup: the elements wrap around the end of the buffer.
+	r := ringbuf.New[int](8)
+	for i := range 6 {
+		r.Push(i)
+	}
+	r.AdvanceHead(5)
+	for i := 6; i < 12; i++ {
+		r.Push(i)
+	}
+
+	// Exercise
+	r.Reserve(20)
+
+	// Verify
+	if r.Cap() != 32 {
+		t.Errorf("Cap() = %v; want 32", r.Cap())
+	}
+	if actual, expected := contents(r), []int{5, 6, 7, 8, 9, 10, 11}; !slices.Equal(actual, expected) {
+		t.Errorf("contents = %v; want %v", actual, expected)
+	}
+	if r.Head() != 5 || r.Tail() != 12 {
+		t.Errorf("Head(), Tail() = %v, %v; want 5, 12", r.Head(), r.Tail())
+	}
+}
+
+func TestRingBuffer_WritableAdvanceTail(t *testing.T) {
+	for _, offset := range []int{0, 3, 7} {
+		// Setup
+		r := ringbuf.New[int](8)
+		r.AdvanceTail(offset)
+		r.AdvanceHead(offset)
+
+		// Exercise
+		a, b := r.Writable()
+		n := copy(a, []int{1, 2, 3, 4, 5})
+		copy(b, []int{1, 2, 3, 4, 5}[n:])
+		r.AdvanceTail(5)
+
+		// Verify
+		if len(a)+len(b) != 8 {
+			t.Errorf("offset %v: Writable() returned %v slots; want 8", offset, len(a)+len(b))
+		}
+		if actual, expected := contents(r), []int{1, 2, 3, 4, 5}; !slices.Equal(actual, expected) {
+			t.Errorf("offset %v: contents = %v; want %v", offset, actual, expected)
+		}
+	}
+}
+
+func TestRingBuffer_CopyInOut(t *testing.T) {
+	// Setup
+	r := ringbuf.New[int](8)
+	r.AdvanceTail(6)
+	r.AdvanceHead(6)
+	r.AdvanceTail(5)
+
+	// Exercise
+	r.CopyIn(0, []int{10, 20, 30, 40, 50})
+	r.Set(1, 21)
+	dst := make([]int, 10)
+	n := r.CopyOut(dst, 1)
+
+	// Verify
+	if n != 4 || !slices.Equal(dst[:n], []int{21, 30, 40, 50}) {
+		t.Errorf("CopyOut(dst, 1) = %v, %v; want 4, [21 30 40 50]", n, dst[:n])
+	}
+	if r.At(4) != 50 {
+		t.Errorf("At(4) = %v; want 50", r.At(4))
+	}
+}
+
+func TestRingBuffer_AdvanceHeadClears(t *testing.T) {
+	r := ringbuf.New[*int](4)
+	x := 1
+	r.Push(&x)
+	r.Push(&x)
+	r.AdvanceHead(2)
+
+	r.AdvanceTail(4)
+	for i := range 4 {
+		if r.At(i) != nil {
+			t.Errorf("slot %v still references a removed element", i)
+		}
+	}
+}
+
+func TestRingBuffer_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(r *ringbuf.RingBuffer[int])
+	}{
+		{"At", func(r *ringbuf.RingBuffer[int]) { r.At(2) }},
+		{"Set", func(r *ringbuf.RingBuffer[int]) { r.Set(-1, 0) }},
+		{"AdvanceHead", func(r *ringbuf.RingBuffer[int]) { r.AdvanceHead(3) }},
+		{"AdvanceTail", func(r *ringbuf.RingBuffer[int]) { r.AdvanceTail(3) }},
+		{"CopyIn", func(r *ringbuf.RingBuffer[int]) { r.CopyIn(2, make([]int, 3)) }},
+		{"CopyOut", func(r *ringbuf.RingBuffer[int]) { r.CopyOut(nil, 3) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := ringbuf.New[int](4)
+			r.Push(1)
+			r.Push(2)
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%v did not panic", test.name)
+				}
+			}()
+			test.f(r)
+		})
+	}
+}
+
+func TestCapacityFor(t *testing.T) {
+	for _, tc := range []struct{ n, expected int }{{0, 0}, {1, 1}, {2, 2}, {3, 4}, {8, 8}, {9, 16}} {
+		if actual := ringbuf.CapacityFor(tc.n); actual != tc.expected {
+			t.Errorf("CapacityFor(%v) = %v; want %v", tc.n, actual, tc.expected)
+		}
+	}
+}
+
+func TestRingBuffer_ResetAt(t *testing.T) {
+	// Setup
+	r := ringbuf.New[int](4)
+	r.Push(1)
+
+	// Exercise
+	r.ResetAt(^uint(0) - 1)
+	for i := range 4 {
+		r.Push(i)
+	}
+
+	// Verify: the positions wrap around the maximum value of uint.
+	if r.Head() != ^uint(0)-1 || r.Tail() != 2 {
+		t.Errorf("Head(), Tail() = %v, %v; want %v, 2", r.Head(), r.Tail(), ^uint(0)-1)
+	}
+	if actual, expected := contents(r), []int{0, 1, 2, 3}; !slices.Equal(actual, expected) {
+		t.Errorf("contents = %v; want %v", actual, expected)
+	}
+	r.Reserve(8)
+	if actual, expected := contents(r), []int{0, 1, 2, 3}; !slices.Equal(actual, expected) {
+		t.Errorf("contents after Reserve = %v; want %v", actual, expected)
+	}
+}
@@ -0,0 +1,84 @@
+package ringbuf
+
+import "slices"
+
+// The functions in this file operate on a bare slice used as a circular buffer.
+// The length of the buffer must be a power of 2, and an unwrapped index i refers to
+// the slot at Index(buffer, i). Indexes may be negative or exceed the length of the buffer.
+// RingBuffer and queue.Queue both implement their index arithmetic and copies with them.
+
+// Index converts an unwrapped index to the corresponding index in the buffer.
+// Index must not be called when the buffer is empty.
+func Index[T any](buffer []T, i int) int {
+	return i & (len(buffer) - 1)
+}
+
+// Regions returns the n slots starting at unwrapped index start as two slices of the buffer.
+// The slots are the concatenation of the first and the second slice.
+// Caller must guarantee that 0 <= n <= len(buffer).
+func Regions[T any](buffer []T, start, n int) ([]T, []T) {
+	if n == 0 {
+		return nil, nil
+	}
+	p := Index(buffer, start)
+	if end := p + n; end <= len(buffer) {
+		return buffer[p:end], nil
+	}
+	return buffer[p:], buffer[:p+n-len(buffer)]
+}
+
+// CopyOut copies len(dst) slots starting at unwrapped index start into dst.
+// Caller must guarantee that len(dst) <= len(buffer).
+func CopyOut[T any](dst, buffer []T, start int) {
+	a, b := Regions(buffer, start, len(dst))
+	k := copy(dst, a)
+	copy(dst[k:], b)
+}
+
+// CopyIn copies src into the slots starting at unwrapped index start.
+// Caller must guarantee that len(src) <= len(buffer).
+func CopyIn[T any](buffer []T, start int, src []T) {
+	a, b := Regions(buffer, start, len(src))
+	k := copy(a, src)
+	copy(b, src[k:])
+}
+
+// Clear sets the n slots starting at unwrapped index start to the zero value.
+// Caller must guarantee that 0 <= n <= len(buffer).
+func Clear[T any](buffer []T, start, n int) {
+	a, b := Regions(buffer, start, n)
+	clear(a)
+	clear(b)
+}
+
+// CopyWithin copies the n slots starting at unwrapped index src to unwrapped index dst.
+// The ranges may overlap. The direction of the copy is chosen by comparing dst and src,
+// so both must be measured from the same origin.
+func CopyWithin[T any](buffer []T, dst, src, n int) {
+	capacity := len(buffer)
+	if dst < src {
+		// Copy front to back so that unread source slots are never overwritten.
+		for n > 0 {
+			d, s := Index(buffer, dst), Index(buffer, src)
+			k := min(n, capacity-d, capacity-s)
+			copy(buffer[d:d+k], buffer[s:s+k])
+			dst, src, n = dst+k, src+k, n-k
+		}
+	} else if dst > src {
+		// Copy back to front for the same reason.
+		for n > 0 {
+			d, s := Index(buffer, dst+n-1)+1, Index(buffer, src+n-1)+1
+			k := min(n, d, s)
+			copy(buffer[d-k:d], buffer[s-k:s])
+			n -= k
+		}
+	}
+}
+
+// RotateLeft rotates the buffer left by k slots, so that the slot at index k moves to index 0.
+// Caller must guarantee that 0 <= k <= len(buffer).
+func RotateLeft[T any](buffer []T, k int) {
+	slices.Reverse(buffer[:k])
+	slices.Reverse(buffer[k:])
+	slices.Reverse(buffer)
+}
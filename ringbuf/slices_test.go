@@ -0,0 +1,101 @@
+package ringbuf_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go/ringbuf"
+)
+
+func TestRegions(t *testing.T) {
+	buffer := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	tests := []struct {
+		start, n      int
+		first, second []int
+	}{
+		{0, 0, nil, nil},
+		{2, 3, []int{2, 3, 4}, nil},
+		{6, 2, []int{6, 7}, nil},
+		{6, 5, []int{6, 7}, []int{0, 1, 2}},
+		{13, 8, []int{5, 6, 7}, []int{0, 1, 2, 3, 4}},
+		{-1, 2, []int{7}, []int{0}},
+	}
+	for _, test := range tests {
+		first, second := ringbuf.Regions(buffer, test.start, test.n)
+		if !slices.Equal(first, test.first) || !slices.Equal(second, test.second) {
+			t.Errorf("Regions(buffer, %v, %v) = %v, %v; want %v, %v",
+				test.start, test.n, first, second, test.first, test.second)
+		}
+	}
+}
+
+func TestCopyInOut(t *testing.T) {
+	// Setup
+	buffer := make([]int, 8)
+
+	// Exercise
+	ringbuf.CopyIn(buffer, 14, []int{1, 2, 3, 4})
+	dst := make([]int, 3)
+	ringbuf.CopyOut(dst, buffer, 15)
+
+	// Verify
+	if expected := []int{3, 4, 0, 0, 0, 0, 1, 2}; !slices.Equal(buffer, expected) {
+		t.Errorf("buffer = %v; want %v", buffer, expected)
+	}
+	if expected := []int{2, 3, 4}; !slices.Equal(dst, expected) {
+		t.Errorf("CopyOut(dst, buffer, 15) = %v; want %v", dst, expected)
+	}
+}
+
+func TestClear(t *testing.T) {
+	buffer := []int{1, 2, 3, 4}
+	ringbuf.Clear(buffer, 3, 2)
+	if expected := []int{0, 2, 3, 0}; !slices.Equal(buffer, expected) {
+		t.Errorf("buffer = %v; want %v", buffer, expected)
+	}
+}
+
+func TestCopyWithin(t *testing.T) {
+	for _, test := range []struct{ dst, src, n int }{
+		{0, 3, 5},
+		{3, 0, 5},
+		{5, 6, 7},
+		{6, 5, 7},
+		{-2, 1, 4},
+		{2, 2, 4},
+	} {
+		for origin := range 8 {
+			// Setup: the slot at unwrapped index origin+i holds i.
+			buffer := make([]int, 8)
+			for i := range buffer {
+				buffer[ringbuf.Index(buffer, origin+i)] = i
+			}
+			expected := slices.Clone(buffer)
+			for k := range test.n {
+				expected[ringbuf.Index(buffer, origin+test.dst+k)] = (test.src + k) & 7
+			}
+
+			// Exercise
+			ringbuf.CopyWithin(buffer, origin+test.dst, origin+test.src, test.n)
+
+			// Verify
+			if !slices.Equal(buffer, expected) {
+				t.Errorf("CopyWithin(buffer, %v, %v, %v) with origin %v: buffer = %v; want %v",
+					test.dst, test.src, test.n, origin, buffer, expected)
+			}
+		}
+	}
+}
+
+func TestRotateLeft(t *testing.T) {
+	for k := range 5 {
+		buffer := []int{0, 1, 2, 3}
+		ringbuf.RotateLeft(buffer, k)
+		for i, x := range buffer {
+			if x != (i+k)%4 {
+				t.Errorf("RotateLeft(buffer, %v) = %v; want the slot at index %v first", k, buffer, k%4)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package ringpipe provides a record-framed pipe built on queue.ByteQueue.
+//
+// A Pipe buffers discrete messages between stages of a parser or a protocol stack.
+// Each record is stored in the ring buffer as a uvarint length prefix followed by its payload,
+// so neither writing nor reading a record allocates once the buffers have grown large enough.
+package ringpipe
+
+import (
+	"encoding/binary"
+
+	"github.com/nojima/queue-go"
+)
+
+// Pipe is a FIFO queue of byte records backed by a single ring buffer of bytes.
+// The zero value for Pipe is an empty pipe ready to use.
+// Pipe is NOT safe for concurrent use.
+type Pipe struct {
+	buffer queue.ByteQueue
+
+	// The number of records in the pipe.
+	records int
+
+	// The buffer that holds the payload returned by the last ReadRecord.
+	scratch []byte
+}
+
+// Len returns the number of records in the pipe.
+func (p *Pipe) Len() int {
+	return p.records
+}
+
+// Buffered returns the number of bytes held by the pipe, including the length prefixes.
+func (p *Pipe) Buffered() int {
+	return p.buffer.Len()
+}
+
+// WriteRecord appends a copy of record to the pipe.
+func (p *Pipe) WriteRecord(record []byte) {
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(len(record)))
+	p.buffer.PushMany(prefix[:n])
+	p.buffer.PushMany(record)
+	p.records++
+}
+
+// ReadRecord removes the first record from the pipe and returns its payload.
+// The returned slice is only valid until the next call of ReadRecord.
+// If the pipe is empty, ReadRecord returns nil and false.
+func (p *Pipe) ReadRecord() ([]byte, bool) {
+	if p.records == 0 {
+		return nil, false
+	}
+
+	length, n := p.peekPrefix()
+	var prefix [binary.MaxVarintLen64]byte
+	p.buffer.Read(prefix[:n])
+
+	if cap(p.scratch) < length {
+		p.scratch = make([]byte, length)
+	}
+	record := p.scratch[:length]
+	p.buffer.Read(record)
+	p.records--
+	return record, true
+}
+
+// PeekLen returns the payload length of the first record without removing it.
+// If the pipe is empty, PeekLen returns 0 and false.
+func (p *Pipe) PeekLen() (int, bool) {
+	if p.records == 0 {
+		return 0, false
+	}
+	length, _ := p.peekPrefix()
+	return length, true
+}
+
+// peekPrefix decodes the length prefix of the first record and returns the payload length
+// and the size of the prefix in bytes.
+func (p *Pipe) peekPrefix() (int, int) {
+	var length uint64
+	for i, shift := 0, 0; ; i, shift = i+1, shift+7 {
+		b := p.buffer.At(i)
+		length |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return int(length), i + 1
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package ringpipe_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nojima/queue-go/ringpipe"
+)
+
+func TestPipe(t *testing.T) {
+	var p ringpipe.Pipe
+	records := [][]byte{
+		[]byte("hello"),
+		{},
+		bytes.Repeat([]byte("x"), 300), // needs a two-byte prefix
+		[]byte("world"),
+	}
+
+	for round := range 3 {
+		for _, r := range records {
+			p.WriteRecord(r)
+		}
+		if p.Len() != len(records) {
+			t.Errorf("round %v: Len() = %v; want %v", round, p.Len(), len(records))
+		}
+
+		for _, expected := range records {
+			if n, ok := p.PeekLen(); n != len(expected) || !ok {
+				t.Errorf("round %v: PeekLen() = %v, %v; want %v, true", round, n, ok, len(expected))
+			}
+			actual, ok := p.ReadRecord()
+			if !ok || !bytes.Equal(actual, expected) {
+				t.Errorf("round %v: ReadRecord() = %q, %v; want %q, true", round, actual, ok, expected)
+			}
+		}
+
+		if actual, ok := p.ReadRecord(); actual != nil || ok {
+			t.Errorf("round %v: ReadRecord() on empty pipe = %q, %v; want nil, false", round, actual, ok)
+		}
+		if p.Buffered() != 0 {
+			t.Errorf("round %v: Buffered() = %v; want 0", round, p.Buffered())
+		}
+	}
+}
+
+func TestPipe_noAllocations(t *testing.T) {
+	var p ringpipe.Pipe
+	record := []byte("message")
+	p.WriteRecord(record)
+	p.ReadRecord()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		p.WriteRecord(record)
+		p.ReadRecord()
+	})
+	if allocs != 0 {
+		t.Errorf("allocations per record: %v; want 0", allocs)
+	}
+}
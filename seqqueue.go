@@ -0,0 +1,88 @@
+package queue
+
+import "iter"
+
+// SeqQueue is a FIFO queue that assigns a monotonically increasing sequence number
+// to every pushed element, starting from zero. Since elements are only removed from the front,
+// the sequence numbers of the pending elements are always contiguous and are not stored.
+// The zero value for SeqQueue is an empty queue ready to use.
+// SeqQueue is NOT safe for concurrent use.
+type SeqQueue[T any] struct {
+	queue Queue[T]
+
+	// The sequence number of the first element in queue.
+	first uint64
+}
+
+// Len returns the number of elements in the queue.
+func (q *SeqQueue[T]) Len() int {
+	return q.queue.Len()
+}
+
+// IsEmpty returns true if the queue is empty.
+func (q *SeqQueue[T]) IsEmpty() bool {
+	return q.queue.IsEmpty()
+}
+
+// NextSeq returns the sequence number that will be assigned to the next pushed element.
+func (q *SeqQueue[T]) NextSeq() uint64 {
+	return q.first + uint64(q.queue.Len())
+}
+
+// SeqRange returns the sequence numbers of the first and the last pending elements.
+// If the queue is empty, SeqRange returns zeros and false.
+func (q *SeqQueue[T]) SeqRange() (first, last uint64, ok bool) {
+	if q.queue.IsEmpty() {
+		return 0, 0, false
+	}
+	return q.first, q.NextSeq() - 1, true
+}
+
+// Push adds an element to the back of the queue and returns its sequence number.
+func (q *SeqQueue[T]) Push(x T) uint64 {
+	seq := q.NextSeq()
+	q.queue.Push(x)
+	return seq
+}
+
+// Pop removes and returns the element at the front of the queue with its sequence number.
+// If the queue is empty, Pop returns the zero value of T, zero and false.
+func (q *SeqQueue[T]) Pop() (T, uint64, bool) {
+	x, ok := q.queue.Pop()
+	if !ok {
+		return x, 0, false
+	}
+	seq := q.first
+	q.first++
+	return x, seq, true
+}
+
+// Peek returns the element at the front of the queue with its sequence number without removing it.
+// If the queue is empty, Peek returns the zero value of T, zero and false.
+func (q *SeqQueue[T]) Peek() (T, uint64, bool) {
+	x, ok := q.queue.Peek()
+	if !ok {
+		return x, 0, false
+	}
+	return x, q.first, true
+}
+
+// At returns the element at the specified index with its sequence number.
+// If the index is out of range, it panics.
+func (q *SeqQueue[T]) At(i int) (T, uint64) {
+	return q.queue.At(i), q.first + uint64(i)
+}
+
+// All returns an iterator over the sequence numbers and the elements in the queue.
+// Do not modify the queue while iterating.
+func (q *SeqQueue[T]) All() iter.Seq2[uint64, T] {
+	return func(yield func(uint64, T) bool) {
+		seq := q.first
+		for x := range q.queue.All() {
+			if !yield(seq, x) {
+				return
+			}
+			seq++
+		}
+	}
+}
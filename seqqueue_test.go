@@ -0,0 +1,44 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestSeqQueue(t *testing.T) {
+	var q queue.SeqQueue[string]
+	if _, _, ok := q.SeqRange(); ok {
+		t.Errorf("SeqRange() on empty queue returned ok")
+	}
+
+	for i, s := range []string{"a", "b", "c"} {
+		if seq := q.Push(s); seq != uint64(i) {
+			t.Errorf("Push(%q) = %v; want %v", s, seq, i)
+		}
+	}
+	if x, seq, ok := q.Pop(); x != "a" || seq != 0 || !ok {
+		t.Errorf("Pop() = %q, %v, %v; want %q, %v, %v", x, seq, ok, "a", 0, true)
+	}
+	if seq := q.Push("d"); seq != 3 {
+		t.Errorf("Push(%q) = %v; want %v", "d", seq, 3)
+	}
+
+	if first, last, ok := q.SeqRange(); first != 1 || last != 3 || !ok {
+		t.Errorf("SeqRange() = %v, %v, %v; want %v, %v, %v", first, last, ok, 1, 3, true)
+	}
+	if x, seq, ok := q.Peek(); x != "b" || seq != 1 || !ok {
+		t.Errorf("Peek() = %q, %v, %v; want %q, %v, %v", x, seq, ok, "b", 1, true)
+	}
+	if x, seq := q.At(2); x != "d" || seq != 3 {
+		t.Errorf("At(2) = %q, %v; want %q, %v", x, seq, "d", 3)
+	}
+
+	expected := uint64(1)
+	for seq, x := range q.All() {
+		if seq != expected {
+			t.Errorf("All() yielded %v, %q; want sequence number %v", seq, x, expected)
+		}
+		expected++
+	}
+}
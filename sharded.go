@@ -0,0 +1,89 @@
+package queue
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ShardedQueue is a FIFO queue for concurrent use that spreads elements over several
+// independently locked shards, so that many goroutines can push and pop in parallel.
+// Pushes and pops are distributed over the shards in round-robin order,
+// which keeps the order approximately FIFO: an element may be popped slightly before
+// an element pushed earlier, but elements from a single shard are never reordered.
+// ShardedQueue is safe for concurrent use by multiple goroutines.
+type ShardedQueue[T any] struct {
+	shards []shard[T]
+
+	// The tickets that choose the shard for the next Push and Pop.
+	// Every Push and Pop writes to them, so each is kept on its own cache line
+	// to avoid false sharing between producers and consumers.
+	pushTicket atomic.Uint64
+	_          [56]byte
+	popTicket  atomic.Uint64
+	_          [56]byte
+
+	// The total number of elements in all shards.
+	length atomic.Int64
+	_      [56]byte
+}
+
+type shard[T any] struct {
+	mu    sync.Mutex
+	queue Queue[T]
+
+	// Keep shards on different cache lines to avoid false sharing.
+	_ [64]byte
+}
+
+// NewShardedQueue returns an empty queue with the given number of shards.
+// A good choice is runtime.GOMAXPROCS(0). If n is not positive, it panics.
+func NewShardedQueue[T any](n int) *ShardedQueue[T] {
+	if n <= 0 {
+		panic(fmt.Sprintf("queue: number of shards must be positive: %d", n))
+	}
+	return &ShardedQueue[T]{shards: make([]shard[T], n)}
+}
+
+// Len returns the total number of elements in all shards.
+// It may be out of date by the time it returns if other goroutines are modifying the queue.
+func (q *ShardedQueue[T]) Len() int {
+	return int(q.length.Load())
+}
+
+// Push adds an element to the back of the next shard.
+func (q *ShardedQueue[T]) Push(x T) {
+	s := &q.shards[q.pushTicket.Add(1)%uint64(len(q.shards))]
+	s.mu.Lock()
+	s.queue.Push(x)
+	// Count the element while holding the lock so that the Pop that takes it
+	// cannot decrement length before it is incremented.
+	q.length.Add(1)
+	s.mu.Unlock()
+}
+
+// Pop removes and returns an element at the front of a shard, starting from the next shard
+// in round-robin order and falling back to the others if it is empty.
+// If all shards are empty, Pop returns the zero value of T and false.
+func (q *ShardedQueue[T]) Pop() (T, bool) {
+	if q.length.Load() <= 0 {
+		var zero T
+		return zero, false
+	}
+
+	start := q.popTicket.Add(1)
+	for i := range uint64(len(q.shards)) {
+		s := &q.shards[(start+i)%uint64(len(q.shards))]
+		s.mu.Lock()
+		x, ok := s.queue.Pop()
+		if ok {
+			q.length.Add(-1)
+		}
+		s.mu.Unlock()
+		if ok {
+			return x, true
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,136 @@
+package queue_test
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestShardedQueue(t *testing.T) {
+	// Setup
+	q := queue.NewShardedQueue[int](4)
+
+	// Exercise
+	for i := range 8 {
+		q.Push(i)
+	}
+	var got []int
+	for {
+		x, ok := q.Pop()
+		if !ok {
+			break
+		}
+		got = append(got, x)
+	}
+
+	// Verify
+	// With a single goroutine, round-robin pushes and pops keep the FIFO order.
+	if want := []int{0, 1, 2, 3, 4, 5, 6, 7}; !slices.Equal(got, want) {
+		t.Errorf("popped %v; want %v", got, want)
+	}
+	if q.Len() != 0 {
+		t.Errorf("Len() = %v; want 0", q.Len())
+	}
+}
+
+func TestShardedQueue_Concurrent(t *testing.T) {
+	// Setup
+	q := queue.NewShardedQueue[int](8)
+	const goroutines, perGoroutine = 8, 1000
+
+	// Exercise
+	var wg sync.WaitGroup
+	popped := make([][]int, goroutines)
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perGoroutine {
+				q.Push(g*perGoroutine + i)
+				if x, ok := q.Pop(); ok {
+					popped[g] = append(popped[g], x)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for {
+		x, ok := q.Pop()
+		if !ok {
+			break
+		}
+		popped[0] = append(popped[0], x)
+	}
+
+	// Verify
+	all := slices.Concat(popped...)
+	slices.Sort(all)
+	if len(all) != goroutines*perGoroutine {
+		t.Fatalf("popped %v elements; want %v", len(all), goroutines*perGoroutine)
+	}
+	for i, x := range all {
+		if x != i {
+			t.Fatalf("element %v is missing or duplicated", i)
+		}
+	}
+}
+
+func TestShardedQueue_LenConsistent(t *testing.T) {
+	// Setup
+	q := queue.NewShardedQueue[int](4)
+	const goroutines, perGoroutine = 8, 2000
+
+	// Exercise: every goroutine pops right after pushing, so the queue is never empty
+	// when Pop is called, even if another goroutine took the element just pushed.
+	var wg sync.WaitGroup
+	failures := make([]int, goroutines)
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perGoroutine {
+				q.Push(i)
+				if _, ok := q.Pop(); !ok || q.Len() < 0 {
+					failures[g]++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Verify
+	for g, n := range failures {
+		if n != 0 {
+			t.Errorf("goroutine %v: Pop() reported empty or Len() was negative %v times", g, n)
+		}
+	}
+	if q.Len() != 0 {
+		t.Errorf("Len() = %v; want 0", q.Len())
+	}
+}
+
+// BenchmarkShardedQueue_parallel and BenchmarkSyncQueue_parallel compare the throughput of
+// concurrent pushes and pops. Sharding only pays off with several cores, e.g. -cpu 8;
+// on a single core the extra tickets make ShardedQueue slower than SyncQueue.
+func BenchmarkShardedQueue_parallel(b *testing.B) {
+	q := queue.NewShardedQueue[int](runtime.GOMAXPROCS(0))
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.Push(1)
+			q.Pop()
+		}
+	})
+}
+
+func BenchmarkSyncQueue_parallel(b *testing.B) {
+	var q queue.SyncQueue[int]
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.Push(1)
+			q.Pop()
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package queue
+
+import (
+	"cmp"
+	"encoding/binary"
+	"hash/maphash"
+	"iter"
+
+	"github.com/nojima/queue-go/ringbuf"
+)
+
+// BinarySearchFunc searches for target in a queue sorted in ascending order as determined by cmp,
+// like slices.BinarySearchFunc. It returns the logical index where target is found,
+// or where it would be inserted, and whether target was found.
+// cmp should return 0 if the element matches the target, a negative number if the element
+// precedes the target, and a positive number if the element follows the target.
+func BinarySearchFunc[T, U any](q *Queue[T], target U, cmp func(T, U) int) (int, bool) {
+	n := q.Len()
+	i, j := 0, n
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if cmp(q.buffer[q.wrap(q.head+h)], target) < 0 {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i, i < n && cmp(q.buffer[q.wrap(q.head+i)], target) == 0
+}
+
+// Index returns the logical index of the first occurrence of x in the queue,
+// or -1 if not present.
+func Index[T comparable](q *Queue[T], x T) int {
+	for i := range q.length {
+		if q.buffer[q.wrap(q.head+i)] == x {
+			return i
+		}
+	}
+	return -1
+}
+
+// Contains reports whether x is present in the queue.
+func Contains[T comparable](q *Queue[T], x T) bool {
+	return Index(q, x) >= 0
+}
+
+// Count returns the number of elements in the queue equal to x.
+func Count[T comparable](q *Queue[T], x T) int {
+	n := 0
+	for i := range q.length {
+		if q.buffer[q.wrap(q.head+i)] == x {
+			n++
+		}
+	}
+	return n
+}
+
+// Equal reports whether two queues contain the same elements in the same FIFO order.
+// The position of the elements in the underlying buffers and the capacities are ignored.
+func Equal[T comparable](q1, q2 *Queue[T]) bool {
+	if q1.Len() != q2.Len() {
+		return false
+	}
+	for i := range q1.length {
+		if q1.buffer[q1.wrap(q1.head+i)] != q2.buffer[q2.wrap(q2.head+i)] {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualFunc reports whether two queues are equal using eq to compare each pair of elements
+// in FIFO order.
+func EqualFunc[T1, T2 any](q1 *Queue[T1], q2 *Queue[T2], eq func(T1, T2) bool) bool {
+	if q1.Len() != q2.Len() {
+		return false
+	}
+	for i := range q1.length {
+		if !eq(q1.buffer[q1.wrap(q1.head+i)], q2.buffer[q2.wrap(q2.head+i)]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare compares the elements of two queues in FIFO order, like slices.Compare.
+// The result is 0 if q1 == q2, -1 if q1 < q2, and +1 if q1 > q2.
+func Compare[T cmp.Ordered](q1, q2 *Queue[T]) int {
+	for i := range min(q1.length, q2.length) {
+		if c := cmp.Compare(q1.buffer[q1.wrap(q1.head+i)], q2.buffer[q2.wrap(q2.head+i)]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(q1.length, q2.length)
+}
+
+// Map returns a new queue containing the results of applying f to each element of q in FIFO order.
+// The buffer of the new queue is allocated once with enough capacity for all the results.
+func Map[T, U any](q *Queue[T], f func(T) U) *Queue[U] {
+	r := &Queue[U]{}
+	if q.IsEmpty() {
+		return r
+	}
+
+	r.buffer = make([]U, ringbuf.CapacityFor(q.length))
+	for i := range q.length {
+		r.buffer[i] = f(q.buffer[q.wrap(q.head+i)])
+	}
+	r.length = q.length
+	return r
+}
+
+// FilterMap returns a new queue containing the results of applying f to each element of q
+// in FIFO order, skipping the elements for which f returns false.
+func FilterMap[T, U any](q *Queue[T], f func(T) (U, bool)) *Queue[U] {
+	r := &Queue[U]{}
+	for i := range q.length {
+		if y, ok := f(q.buffer[q.wrap(q.head+i)]); ok {
+			r.Push(y)
+		}
+	}
+	return r
+}
+
+// Reduce folds the elements of q in FIFO order, starting from init and
+// replacing the accumulator with f(accumulator, element) for each element.
+func Reduce[T, A any](q *Queue[T], init A, f func(A, T) A) A {
+	acc := init
+	for i := range q.length {
+		acc = f(acc, q.buffer[q.wrap(q.head+i)])
+	}
+	return acc
+}
+
+// Flatten returns an iterator over the elements of all inner queues of q in order:
+// the elements of the first inner queue in FIFO order, then those of the second, and so on.
+// Nil inner queues are skipped.
+// Do not modify q or the inner queues while iterating; the iterator panics if it detects a modification.
+func Flatten[T any](q *Queue[*Queue[T]]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for inner := range q.All() {
+			if inner == nil {
+				continue
+			}
+			for x := range inner.All() {
+				if !yield(x) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Hash returns an order-sensitive hash of the elements of q, combining the hashes
+// returned by hashElem in FIFO order. Queues with equal elements in the same order have
+// the same hash for the same seed, regardless of their capacities or the positions of
+// the elements in the buffers.
+func Hash[T any](seed maphash.Seed, q *Queue[T], hashElem func(maphash.Seed, T) uint64) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(q.length))
+	h.Write(buf[:])
+	for i := range q.length {
+		binary.LittleEndian.PutUint64(buf[:], hashElem(seed, q.buffer[q.wrap(q.head+i)]))
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
+
+// Zip returns an iterator over pairs of elements of a and b at the same logical index,
+// in FIFO order, up to the length of the shorter queue.
+// Do not modify the queues while iterating; the iterator panics if it detects a modification.
+func Zip[A, B any](a *Queue[A], b *Queue[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		versionA, versionB := a.version, b.version
+		for i := range min(a.length, b.length) {
+			if !yield(a.buffer[a.wrap(a.head+i)], b.buffer[b.wrap(b.head+i)]) {
+				break
+			}
+			a.checkVersion(versionA)
+			b.checkVersion(versionB)
+		}
+	}
+}
+
+// PopGroup removes and returns the element at the front of q together with all
+// immediately following elements that have the same key, in FIFO order.
+// The elements are removed with bulk copies. If q is empty, PopGroup returns nil.
+// It is a function rather than a method because methods cannot have type parameters.
+func PopGroup[T any, K comparable](q *Queue[T], key func(T) K) []T {
+	if q.IsEmpty() {
+		return nil
+	}
+
+	k := key(q.buffer[q.head])
+	n := 1
+	for n < q.length && key(q.buffer[q.wrap(q.head+n)]) == k {
+		n++
+	}
+	group := make([]T, n)
+	q.popInto(group)
+	return group
+}
+
+// Partition returns two new queues: match with the elements of q that satisfy pred,
+// and rest with the others, both keeping the relative order of the elements.
+// Runs of consecutive elements that go to the same queue are moved with bulk copies.
+// q is not modified.
+func Partition[T any](q *Queue[T], pred func(T) bool) (match, rest *Queue[T]) {
+	match, rest = &Queue[T]{}, &Queue[T]{}
+	first, second := q.segments()
+	for _, segment := range [...][]T{first, second} {
+		if len(segment) == 0 {
+			continue
+		}
+		// Evaluate pred once per element, remembering the result for the start of the next run.
+		start, ok := 0, pred(segment[0])
+		for start < len(segment) {
+			end, next := start+1, ok
+			for end < len(segment) {
+				if next = pred(segment[end]); next != ok {
+					break
+				}
+				end++
+			}
+			if ok {
+				match.PushMany(segment[start:end])
+			} else {
+				rest.PushMany(segment[start:end])
+			}
+			start, ok = end, next
+		}
+	}
+	return match, rest
+}
+
+// Compact replaces each run of consecutive equal elements of q with a single copy,
+// like slices.Compact. See (*Queue).CompactFunc.
+func Compact[T comparable](q *Queue[T]) {
+	q.CompactFunc(func(a, b T) bool { return a == b })
+}
@@ -0,0 +1,395 @@
+package queue_test
+
+import (
+	"cmp"
+	"fmt"
+	"hash/maphash"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestBinarySearchFunc(t *testing.T) {
+	testCases := []struct {
+		title         string
+		target        int
+		expectedIndex int
+		expectedFound bool
+	}{
+		{
+			title:         "before first",
+			target:        0,
+			expectedIndex: 0,
+			expectedFound: false,
+		},
+		{
+			title:         "first",
+			target:        1,
+			expectedIndex: 0,
+			expectedFound: true,
+		},
+		{
+			title:         "middle",
+			target:        5,
+			expectedIndex: 4,
+			expectedFound: true,
+		},
+		{
+			title:         "missing",
+			target:        6,
+			expectedIndex: 5,
+			expectedFound: false,
+		},
+		{
+			title:         "after last",
+			target:        10,
+			expectedIndex: 7,
+			expectedFound: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("%s/offset=%d", tc.title, offset), func(t *testing.T) {
+				// Setup
+				q := newQueue(offset, 1, 1, 2, 3, 5, 8, 9)
+
+				// Exercise
+				i, found := queue.BinarySearchFunc(q, tc.target, cmp.Compare[int])
+
+				// Verify
+				if i != tc.expectedIndex || found != tc.expectedFound {
+					t.Errorf("BinarySearchFunc(%v) = %v, %v; want %v, %v",
+						tc.target, i, found, tc.expectedIndex, tc.expectedFound)
+				}
+			})
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	testCases := []struct {
+		title    string
+		x        int
+		expected int
+	}{
+		{
+			title:    "first",
+			x:        3,
+			expected: 0,
+		},
+		{
+			title:    "first occurrence",
+			x:        1,
+			expected: 1,
+		},
+		{
+			title:    "last",
+			x:        2,
+			expected: 6,
+		},
+		{
+			title:    "missing",
+			x:        7,
+			expected: -1,
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("%s/offset=%d", tc.title, offset), func(t *testing.T) {
+				q := newQueue(offset, 3, 1, 4, 1, 5, 9, 2)
+
+				actual := queue.Index(q, tc.x)
+				if actual != tc.expected {
+					t.Errorf("Index(%v) = %v; want %v", tc.x, actual, tc.expected)
+				}
+				if contains := queue.Contains(q, tc.x); contains != (tc.expected >= 0) {
+					t.Errorf("Contains(%v) = %v; want %v", tc.x, contains, tc.expected >= 0)
+				}
+			})
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			q := newQueue(offset, 3, 1, 4, 1, 5, 9, 2)
+
+			for x, expected := range map[int]int{1: 2, 3: 1, 7: 0} {
+				if actual := queue.Count(q, x); actual != expected {
+					t.Errorf("Count(%v) = %v; want %v", x, actual, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	testCases := []struct {
+		title    string
+		q1       []int
+		q2       []int
+		expected int
+	}{
+		{
+			title:    "empty",
+			q1:       []int{},
+			q2:       []int{},
+			expected: 0,
+		},
+		{
+			title:    "equal",
+			q1:       []int{3, 1, 4},
+			q2:       []int{3, 1, 4},
+			expected: 0,
+		},
+		{
+			title:    "less",
+			q1:       []int{3, 1, 4},
+			q2:       []int{3, 1, 5},
+			expected: -1,
+		},
+		{
+			title:    "greater",
+			q1:       []int{3, 2},
+			q2:       []int{3, 1, 5},
+			expected: +1,
+		},
+		{
+			title:    "prefix",
+			q1:       []int{3, 1},
+			q2:       []int{3, 1, 4},
+			expected: -1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			// Use different offsets so that the elements are placed differently in the buffers.
+			q1 := newQueue(0, tc.q1...)
+			q2 := newQueue(7, tc.q2...)
+
+			if actual := queue.Compare(q1, q2); actual != tc.expected {
+				t.Errorf("Compare() = %v; want %v", actual, tc.expected)
+			}
+			if actual := queue.Equal(q1, q2); actual != (tc.expected == 0) {
+				t.Errorf("Equal() = %v; want %v", actual, tc.expected == 0)
+			}
+			eq := func(a, b int) bool { return a == b }
+			if actual := queue.EqualFunc(q1, q2, eq); actual != (tc.expected == 0) {
+				t.Errorf("EqualFunc() = %v; want %v", actual, tc.expected == 0)
+			}
+		})
+	}
+}
+
+func TestMap(t *testing.T) {
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			q := newQueue(offset, 3, 1, 4, 1, 5)
+
+			actual := slices.Collect(queue.Map(q, strconv.Itoa).All())
+			expected := []string{"3", "1", "4", "1", "5"}
+			if !slices.Equal(actual, expected) {
+				t.Errorf("actual: %v; want: %v", actual, expected)
+			}
+		})
+	}
+}
+
+func TestFilterMap(t *testing.T) {
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			q := newQueue(offset, 3, 1, 4, 1, 5)
+
+			r := queue.FilterMap(q, func(x int) (string, bool) {
+				return strconv.Itoa(x), x != 1
+			})
+
+			actual := slices.Collect(r.All())
+			expected := []string{"3", "4", "5"}
+			if !slices.Equal(actual, expected) {
+				t.Errorf("actual: %v; want: %v", actual, expected)
+			}
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			q := newQueue(offset, 3, 1, 4, 1, 5)
+
+			actual := queue.Reduce(q, "", func(acc string, x int) string {
+				return acc + strconv.Itoa(x)
+			})
+			if actual != "31415" {
+				t.Errorf("Reduce() = %q; want %q", actual, "31415")
+			}
+		})
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			var q queue.Queue[*queue.Queue[int]]
+			q.Push(newQueue(offset, 3, 1, 4))
+			q.Push(nil)
+			q.Push(newQueue(offset))
+			q.Push(newQueue(offset, 1, 5))
+
+			actual := slices.Collect(queue.Flatten(&q))
+			if want := []int{3, 1, 4, 1, 5}; !slices.Equal(actual, want) {
+				t.Errorf("Flatten() = %v; want %v", actual, want)
+			}
+
+			// Breaking out of the loop stops the iteration.
+			var first []int
+			for x := range queue.Flatten(&q) {
+				if len(first) == 4 {
+					break
+				}
+				first = append(first, x)
+			}
+			if want := []int{3, 1, 4, 1}; !slices.Equal(first, want) {
+				t.Errorf("Flatten() with break = %v; want %v", first, want)
+			}
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	seed := maphash.MakeSeed()
+	hashInt := func(seed maphash.Seed, x int) uint64 {
+		return maphash.String(seed, strconv.Itoa(x))
+	}
+
+	h := queue.Hash(seed, newQueue(0, 3, 1, 4), hashInt)
+	if actual := queue.Hash(seed, newQueue(5, 3, 1, 4), hashInt); actual != h {
+		t.Errorf("Hash() differs for equal queues with different offsets")
+	}
+	for _, other := range [][]int{{3, 4, 1}, {3, 1}, {3, 1, 4, 1}, {}} {
+		if actual := queue.Hash(seed, newQueue(0, other...), hashInt); actual == h {
+			t.Errorf("Hash(%v) == Hash(%v)", other, []int{3, 1, 4})
+		}
+	}
+}
+
+func TestZip(t *testing.T) {
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			a := newQueue(offset, 3, 1, 4, 1, 5)
+			var b queue.Queue[string]
+			for _, s := range []string{"c", "a", "d"} {
+				b.Push(s)
+			}
+
+			var actual []string
+			for x, s := range queue.Zip(a, &b) {
+				actual = append(actual, strconv.Itoa(x)+s)
+			}
+			if expected := []string{"3c", "1a", "4d"}; !slices.Equal(actual, expected) {
+				t.Errorf("Zip() = %v; want %v", actual, expected)
+			}
+		})
+	}
+}
+
+func TestPopGroup(t *testing.T) {
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			// Setup
+			q := newQueue(offset, 11, 12, 13, 21, 31, 32, 11)
+			tens := func(x int) int { return x / 10 }
+
+			// Exercise
+			var groups [][]int
+			for !q.IsEmpty() {
+				groups = append(groups, queue.PopGroup(q, tens))
+			}
+
+			// Verify
+			expected := [][]int{{11, 12, 13}, {21}, {31, 32}, {11}}
+			if !slices.EqualFunc(groups, expected, slices.Equal) {
+				t.Errorf("groups: %v; want: %v", groups, expected)
+			}
+			if g := queue.PopGroup(q, tens); g != nil {
+				t.Errorf("PopGroup() on empty queue = %v; want nil", g)
+			}
+		})
+	}
+}
+
+func TestPartition(t *testing.T) {
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			// Setup
+			q := newQueue(offset, 3, 1, 4, 1, 5, 9, 2, 6, 5, 3, 5)
+
+			// Exercise
+			calls := 0
+			match, rest := queue.Partition(q, func(x int) bool {
+				calls++
+				return x%2 == 1
+			})
+
+			// Verify
+			if actual, want := slices.Collect(match.All()), []int{3, 1, 1, 5, 9, 5, 3, 5}; !slices.Equal(actual, want) {
+				t.Errorf("match: %v; want: %v", actual, want)
+			}
+			if actual, want := slices.Collect(rest.All()), []int{4, 2, 6}; !slices.Equal(actual, want) {
+				t.Errorf("rest: %v; want: %v", actual, want)
+			}
+			if q.Len() != 11 || calls != 11 {
+				t.Errorf("Len() = %v, pred called %v times; want 11, 11", q.Len(), calls)
+			}
+		})
+	}
+}
+
+func TestCompact(t *testing.T) {
+	testCases := []struct {
+		title    string
+		elements []int
+	}{
+		{
+			title:    "empty",
+			elements: []int{},
+		},
+		{
+			title:    "no duplicates",
+			elements: []int{3, 1, 4, 1, 5},
+		},
+		{
+			title:    "runs",
+			elements: []int{3, 3, 3, 1, 4, 4, 1, 5, 5},
+		},
+		{
+			title:    "all equal",
+			elements: []int{7, 7, 7, 7, 7, 7, 7},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, offset := range []int{0, 5} {
+			t.Run(fmt.Sprintf("%s/offset=%d", tc.title, offset), func(t *testing.T) {
+				// Setup
+				q := newQueue(offset, tc.elements...)
+
+				// Exercise
+				queue.Compact(q)
+
+				// Verify
+				expected := slices.Compact(slices.Clone(tc.elements))
+				if actual := slices.Collect(q.All()); !slices.Equal(actual, expected) {
+					t.Errorf("actual: %v; want: %v", actual, expected)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package queue
+
+import "sort"
+
+// SortInterface returns a sort.Interface over the elements of the queue in FIFO order,
+// ordered by less. Swap exchanges the elements in the queue in place, so code written
+// for sort.Interface, such as sort.Sort and sort.Stable, can operate on the queue without
+// copying it to a slice. The returned value is valid until the length of the queue changes.
+func (q *Queue[T]) SortInterface(less func(a, b T) bool) sort.Interface {
+	return sortAdapter[T]{q: q, less: less}
+}
+
+type sortAdapter[T any] struct {
+	q    *Queue[T]
+	less func(a, b T) bool
+}
+
+func (s sortAdapter[T]) Len() int {
+	return s.q.length
+}
+
+func (s sortAdapter[T]) Less(i, j int) bool {
+	return s.less(s.q.At(i), s.q.At(j))
+}
+
+func (s sortAdapter[T]) Swap(i, j int) {
+	x, y := s.q.At(i), s.q.At(j)
+	s.q.version++
+	if raceGuard {
+		s.q.guard.enter("Swap")
+		defer s.q.guard.exit()
+	}
+	s.q.buffer[s.q.wrap(s.q.head+i)] = y
+	s.q.buffer[s.q.wrap(s.q.head+j)] = x
+}
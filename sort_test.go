@@ -0,0 +1,33 @@
+package queue_test
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestQueue_SortInterface(t *testing.T) {
+	for _, offset := range []int{0, 5} {
+		t.Run(fmt.Sprintf("offset=%d", offset), func(t *testing.T) {
+			// Setup
+			q := newQueue(offset, 3, 1, 4, 1, 5, 9, 2, 6)
+
+			// Exercise
+			sort.Sort(q.SortInterface(func(a, b int) bool { return a < b }))
+
+			// Verify
+			if actual, expected := slices.Collect(q.All()), []int{1, 1, 2, 3, 4, 5, 6, 9}; !slices.Equal(actual, expected) {
+				t.Errorf("sorted: %v; want: %v", actual, expected)
+			}
+
+			// Exercise
+			sort.Stable(q.SortInterface(func(a, b int) bool { return a%2 < b%2 }))
+
+			// Verify
+			if actual, expected := slices.Collect(q.All()), []int{2, 4, 6, 1, 1, 3, 5, 9}; !slices.Equal(actual, expected) {
+				t.Errorf("stable sorted: %v; want: %v", actual, expected)
+			}
+		})
+	}
+}
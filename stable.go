@@ -0,0 +1,114 @@
+package queue
+
+import "fmt"
+
+// The number of elements in a block of StableQueue.
+const stableBlockSize = 64
+
+// StableQueue is a FIFO queue whose elements never move in memory while they are in the queue.
+// The elements are stored in fixed-size blocks that are allocated as the queue grows,
+// so the pointers returned by PushRef and AtRef remain valid until the element is popped,
+// which allows large elements to be updated in place.
+// The zero value for StableQueue is an empty queue ready to use.
+// StableQueue is NOT safe for concurrent use.
+type StableQueue[T any] struct {
+	// The blocks storing the elements. Only the elements in the logical range
+	// [head, head+length) of the concatenation of the blocks are in the queue.
+	blocks Queue[*[stableBlockSize]T]
+
+	// The index of the first element in the first block.
+	// Invariant: 0 <= head < stableBlockSize
+	head int
+
+	// The number of elements in the queue.
+	length int
+
+	// An emptied block kept for reuse, or nil.
+	spare *[stableBlockSize]T
+}
+
+// Len returns the number of elements in the queue.
+func (q *StableQueue[T]) Len() int {
+	return q.length
+}
+
+// IsEmpty returns true if the queue is empty.
+func (q *StableQueue[T]) IsEmpty() bool {
+	return q.length == 0
+}
+
+// Push adds an element to the back of the queue.
+func (q *StableQueue[T]) Push(x T) {
+	q.PushRef(x)
+}
+
+// PushRef adds an element to the back of the queue and returns a pointer to it.
+// The pointer remains valid until the element is popped.
+func (q *StableQueue[T]) PushRef(x T) *T {
+	pos := q.head + q.length
+	if pos/stableBlockSize == q.blocks.Len() {
+		block := q.spare
+		q.spare = nil
+		if block == nil {
+			block = new([stableBlockSize]T)
+		}
+		q.blocks.Push(block)
+	}
+	p := q.ref(pos)
+	*p = x
+	q.length++
+	return p
+}
+
+// Pop removes and returns the element at the front of the queue.
+// A pointer to the element obtained before must not be used after it is popped.
+// If the queue is empty, Pop returns the zero value of T and false.
+func (q *StableQueue[T]) Pop() (T, bool) {
+	if q.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+
+	p := q.ref(q.head)
+	x := *p
+	var zero T
+	*p = zero
+	q.head++
+	q.length--
+	if q.head == stableBlockSize {
+		q.spare, _ = q.blocks.Pop()
+		q.head = 0
+	}
+	return x, true
+}
+
+// Peek returns the element at the front of the queue without removing it.
+// If the queue is empty, Peek returns the zero value of T and false.
+func (q *StableQueue[T]) Peek() (T, bool) {
+	if q.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+	return *q.ref(q.head), true
+}
+
+// At returns the element at the specified index.
+// If the index is out of range, it panics.
+func (q *StableQueue[T]) At(i int) T {
+	return *q.AtRef(i)
+}
+
+// AtRef returns a pointer to the element at the specified index.
+// The pointer remains valid until the element is popped.
+// If the index is out of range, it panics.
+func (q *StableQueue[T]) AtRef(i int) *T {
+	if i < 0 || i >= q.length {
+		panic(fmt.Sprintf("queue: index out of range: i=%d, len=%d", i, q.length))
+	}
+	return q.ref(q.head + i)
+}
+
+// ref returns a pointer to the slot at the position pos in the concatenation of the blocks.
+func (q *StableQueue[T]) ref(pos int) *T {
+	return &q.blocks.At(pos / stableBlockSize)[pos%stableBlockSize]
+}
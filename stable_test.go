@@ -0,0 +1,68 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestStableQueue(t *testing.T) {
+	type large struct {
+		id      int
+		payload [16]int
+	}
+
+	// Setup
+	var q queue.StableQueue[large]
+	var refs []*large
+	for i := range 300 {
+		refs = append(refs, q.PushRef(large{id: i}))
+	}
+
+	// Exercise
+	// Pop some elements and push more so that blocks are released and allocated.
+	for i := range 100 {
+		x, ok := q.Pop()
+		if !ok || x.id != i {
+			t.Fatalf("Pop() = (%v, %v); want id %v", x.id, ok, i)
+		}
+	}
+	for i := 300; i < 500; i++ {
+		refs = append(refs, q.PushRef(large{id: i}))
+	}
+
+	// Verify
+	if q.Len() != 400 {
+		t.Errorf("Len() = %v; want 400", q.Len())
+	}
+	for i := range q.Len() {
+		p := q.AtRef(i)
+		if p != refs[100+i] {
+			t.Fatalf("AtRef(%v) = %p; want the pointer returned by PushRef %p", i, p, refs[100+i])
+		}
+		if p.id != 100+i {
+			t.Fatalf("AtRef(%v).id = %v; want %v", i, p.id, 100+i)
+		}
+		p.payload[0] = p.id * 2
+	}
+	for i := 100; i < 500; i++ {
+		x, _ := q.Pop()
+		if x.id != i || x.payload[0] != i*2 {
+			t.Fatalf("Pop() = {id: %v, payload[0]: %v}; want {id: %v, payload[0]: %v}", x.id, x.payload[0], i, i*2)
+		}
+	}
+	if _, ok := q.Pop(); ok {
+		t.Errorf("Pop() on empty queue returned ok")
+	}
+}
+
+func TestStableQueue_AtRefOutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("AtRef(1) did not panic")
+		}
+	}()
+	var q queue.StableQueue[int]
+	q.Push(1)
+	q.AtRef(1)
+}
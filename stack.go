@@ -0,0 +1,96 @@
+package queue
+
+import "iter"
+
+// Stack is a LIFO stack backed by a slice.
+// The zero value for Stack is an empty stack ready to use.
+// Stack is NOT safe for concurrent use.
+type Stack[T any] struct {
+	// The elements in the stack. The top of the stack is the last element.
+	elements []T
+
+	// The modification counter, incremented by every operation that modifies the stack.
+	// Iterators use it to detect modifications during iteration.
+	version uint
+}
+
+// Len returns the number of elements in the stack.
+func (s *Stack[T]) Len() int {
+	return len(s.elements)
+}
+
+// IsEmpty returns true if the stack is empty.
+func (s *Stack[T]) IsEmpty() bool {
+	return len(s.elements) == 0
+}
+
+// Push adds an element to the top of the stack.
+func (s *Stack[T]) Push(x T) {
+	s.version++
+	s.elements = append(s.elements, x)
+}
+
+// Pop removes and returns the element at the top of the stack.
+// If the stack is empty, Pop returns the zero value of T and false.
+func (s *Stack[T]) Pop() (T, bool) {
+	if s.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+
+	s.version++
+	last := len(s.elements) - 1
+	x := s.elements[last]
+	// Clear the slot so that the stack does not keep the element alive.
+	var zero T
+	s.elements[last] = zero
+	s.elements = s.elements[:last]
+	return x, true
+}
+
+// Peek returns the element at the top of the stack without removing it.
+// If the stack is empty, Peek returns the zero value of T and false.
+func (s *Stack[T]) Peek() (T, bool) {
+	if s.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+
+	return s.elements[len(s.elements)-1], true
+}
+
+// All returns an iterator over all elements in the stack from top to bottom,
+// that is, in the order Pop would return them.
+// Do not modify the stack while iterating; the iterator panics if it detects a modification.
+func (s *Stack[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		version := s.version
+		for i := len(s.elements) - 1; i >= 0; i-- {
+			if !yield(s.elements[i]) {
+				break
+			}
+			s.checkVersion(version)
+		}
+	}
+}
+
+// Backward returns an iterator over all elements in the stack from bottom to top (oldest first).
+// Do not modify the stack while iterating; the iterator panics if it detects a modification.
+func (s *Stack[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		version := s.version
+		for i := range len(s.elements) {
+			if !yield(s.elements[i]) {
+				break
+			}
+			s.checkVersion(version)
+		}
+	}
+}
+
+// checkVersion panics if the stack has been modified since the iterator observed version.
+func (s *Stack[T]) checkVersion(version uint) {
+	if s.version != version {
+		panic("queue: stack modified during iteration")
+	}
+}
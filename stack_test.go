@@ -0,0 +1,104 @@
+package queue_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func ExampleStack() {
+	var s queue.Stack[int]
+	s.Push(3)
+	s.Push(1)
+	s.Push(4)
+
+	for !s.IsEmpty() {
+		x, _ := s.Pop()
+		fmt.Println(x)
+	}
+	// Output:
+	// 4
+	// 1
+	// 3
+}
+
+func TestStack(t *testing.T) {
+	testCases := []struct {
+		title    string
+		elements []int
+	}{
+		{
+			title:    "empty",
+			elements: []int{},
+		},
+		{
+			title:    "one",
+			elements: []int{1},
+		},
+		{
+			title:    "many",
+			elements: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			// Setup
+			var s queue.Stack[int]
+			for _, x := range tc.elements {
+				s.Push(x)
+			}
+
+			// Verify
+			reversed := slices.Clone(tc.elements)
+			slices.Reverse(reversed)
+			if s.Len() != len(tc.elements) {
+				t.Errorf("Len() = %v; want %v", s.Len(), len(tc.elements))
+			}
+			if got := slices.Collect(s.All()); !slices.Equal(got, reversed) {
+				t.Errorf("All() = %v; want %v", got, reversed)
+			}
+			if got := slices.Collect(s.Backward()); !slices.Equal(got, tc.elements) {
+				t.Errorf("Backward() = %v; want %v", got, tc.elements)
+			}
+
+			// Exercise
+			var popped []int
+			for {
+				top, peekOK := s.Peek()
+				x, ok := s.Pop()
+				if ok != peekOK || x != top {
+					t.Fatalf("Peek() = (%v, %v) but Pop() = (%v, %v)", top, peekOK, x, ok)
+				}
+				if !ok {
+					break
+				}
+				popped = append(popped, x)
+			}
+
+			// Verify
+			if !slices.Equal(popped, reversed) {
+				t.Errorf("popped %v; want %v", popped, reversed)
+			}
+			if !s.IsEmpty() {
+				t.Errorf("IsEmpty() = false after popping all elements")
+			}
+		})
+	}
+}
+
+func TestStack_ModifiedDuringIteration(t *testing.T) {
+	var s queue.Stack[int]
+	s.Push(1)
+	s.Push(2)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("All() did not panic when the stack was modified")
+		}
+	}()
+	for x := range s.All() {
+		s.Push(x)
+	}
+}
@@ -0,0 +1,64 @@
+package queue
+
+// Stats holds statistics of a queue collected since EnableStats was called.
+type Stats struct {
+	// Peak is the maximum number of elements the queue has held.
+	Peak int
+
+	// Pushes is the total number of elements added to the queue.
+	Pushes uint64
+
+	// Pops is the total number of elements removed from the queue.
+	Pops uint64
+
+	// Grows is the number of times the buffer has been reallocated to grow.
+	Grows uint64
+}
+
+// EnableStats starts collecting statistics of the queue.
+// Statistics are not collected by default to keep the operations as cheap as possible.
+// Calling EnableStats again has no effect.
+func (q *Queue[T]) EnableStats() {
+	if e := q.ensureExtensions(); e.stats == nil {
+		e.stats = &Stats{Peak: q.length}
+	}
+}
+
+// Stats returns the statistics of the queue.
+// If EnableStats has not been called, Stats returns the zero value.
+func (q *Queue[T]) Stats() Stats {
+	if q.ext == nil || q.ext.stats == nil {
+		return Stats{}
+	}
+	return *q.ext.stats
+}
+
+// added records that n elements have been added to the queue.
+// Caller must call it after updating length.
+func (q *Queue[T]) added(n int) {
+	e := q.ext
+	if e == nil {
+		return
+	}
+	if e.stats != nil {
+		e.stats.Pushes += uint64(n)
+		e.stats.Peak = max(e.stats.Peak, q.length)
+	}
+	if e.adaptive != nil {
+		e.adaptive.peak = max(e.adaptive.peak, q.length)
+	}
+	q.checkWatermarks()
+}
+
+// removed records that n elements have been removed from the queue.
+// Caller must call it after updating length.
+func (q *Queue[T]) removed(n int) {
+	e := q.ext
+	if e == nil {
+		return
+	}
+	if e.stats != nil {
+		e.stats.Pops += uint64(n)
+	}
+	q.checkWatermarks()
+}
@@ -0,0 +1,44 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestQueue_Stats(t *testing.T) {
+	// Setup
+	var q queue.Queue[int]
+	q.Push(0) // not counted
+	q.EnableStats()
+
+	// Exercise
+	q.PushMany([]int{3, 1, 4, 1, 5})
+	q.Pop()
+	q.Pop()
+	q.InsertAt(1, 9)
+	q.RemoveAt(0)
+	q.Push(2)
+	q.Truncate(2)
+
+	// Verify
+	actual := q.Stats()
+	expected := queue.Stats{
+		Peak:   6,
+		Pushes: 7,
+		Pops:   6,
+		Grows:  1,
+	}
+	if actual != expected {
+		t.Errorf("actual: %+v; want: %+v", actual, expected)
+	}
+}
+
+func TestQueue_Stats_disabled(t *testing.T) {
+	var q queue.Queue[int]
+	q.PushMany([]int{3, 1, 4})
+
+	if actual := q.Stats(); actual != (queue.Stats{}) {
+		t.Errorf("actual: %+v; want: %+v", actual, queue.Stats{})
+	}
+}
@@ -0,0 +1,388 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by operations on a SyncQueue that has been closed.
+var ErrClosed = errors.New("queue: queue closed")
+
+// SyncQueue is a FIFO queue that is safe for concurrent use by multiple goroutines.
+// Besides non-blocking operations, it provides PopWait, which blocks until an element
+// is available, and NotEmpty, which allows waiting for elements in a select statement.
+// Producers call Close to signal the end of the stream.
+// The zero value for SyncQueue is an empty queue ready to use.
+// A SyncQueue must not be copied after first use.
+type SyncQueue[T any] struct {
+	mu     sync.Mutex
+	queue  Queue[T]
+	closed bool
+
+	// The channel returned by NotEmpty.
+	// Invariant: ready == nil, or ready is closed if and only if queue is not empty or closed is true.
+	ready chan struct{}
+
+	// The channel closed on the next push or close, or nil if nobody is waiting for it.
+	changed chan struct{}
+}
+
+// Len returns the number of elements in the queue.
+func (q *SyncQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Len()
+}
+
+// AsFIFO returns an adapter of q that implements FIFO.
+// Its Push panics if q has been closed, like a send on a closed channel.
+func (q *SyncQueue[T]) AsFIFO() FIFO[T] {
+	return syncFIFO[T]{q}
+}
+
+// Push adds an element to the back of the queue.
+// If the queue has been closed, Push returns ErrClosed and the element is not added.
+func (q *SyncQueue[T]) Push(x T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrClosed
+	}
+	q.queue.Push(x)
+	q.notifyLocked()
+	return nil
+}
+
+// PushMany adds multiple elements to the back of the queue at once.
+// If the queue has been closed, PushMany returns ErrClosed and no elements are added.
+func (q *SyncQueue[T]) PushMany(xs []T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrClosed
+	}
+	q.queue.PushMany(xs)
+	q.notifyLocked()
+	return nil
+}
+
+// The maximum number of elements PushFromChan pushes at once.
+const pushFromChanBatchSize = 64
+
+// PushFromChan receives values from ch and pushes them to the queue until ch is closed,
+// in which case it returns nil. Values that are immediately available are pushed together
+// with PushMany to reduce lock contention. If ctx is done, PushFromChan returns the error of ctx,
+// and if the queue is closed, it returns ErrClosed and the values received in the last batch
+// are lost. PushFromChan does not close the queue.
+func (q *SyncQueue[T]) PushFromChan(ctx context.Context, ch <-chan T) error {
+	batch := make([]T, 0, pushFromChanBatchSize)
+	for {
+		select {
+		case x, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			batch = append(batch[:0], x)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		open := true
+	collect:
+		for len(batch) < cap(batch) {
+			select {
+			case x, ok := <-ch:
+				if !ok {
+					open = false
+					break collect
+				}
+				batch = append(batch, x)
+			default:
+				break collect
+			}
+		}
+
+		err := q.PushMany(batch)
+		clear(batch)
+		if err != nil {
+			return err
+		}
+		if !open {
+			return nil
+		}
+	}
+}
+
+// Close closes the queue. Subsequent pushes fail with ErrClosed.
+// Elements already in the queue can still be popped; once they are drained,
+// PopWait returns ErrClosed instead of blocking.
+// Closing a closed queue has no effect.
+func (q *SyncQueue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.notifyLocked()
+}
+
+// Pop removes and returns the element at the front of the queue without blocking.
+// If the queue is empty, Pop returns the zero value of T and false.
+func (q *SyncQueue[T]) Pop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.popLocked()
+}
+
+// PopWait removes and returns the element at the front of the queue,
+// waiting until an element is available. If the queue is closed and empty,
+// PopWait returns the zero value of T and ErrClosed. If ctx is done before
+// an element is available, PopWait returns the zero value of T and the error of ctx.
+func (q *SyncQueue[T]) PopWait(ctx context.Context) (T, error) {
+	for {
+		q.mu.Lock()
+		x, ok := q.popLocked()
+		closed := q.closed
+		ready := q.readyLocked()
+		q.mu.Unlock()
+		if ok {
+			return x, nil
+		}
+		if closed {
+			return x, ErrClosed
+		}
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Drain returns an iterator that pops and yields elements, waiting while the queue is empty,
+// until ctx is done or the queue is closed and drained. It is the canonical consumer loop:
+//
+//	for x := range q.Drain(ctx) {
+//		handle(x)
+//	}
+//
+// Check ctx.Err() after the loop to tell whether it ended by cancellation.
+// Each element is removed before it is yielded, so breaking out of the loop loses nothing.
+func (q *SyncQueue[T]) Drain(ctx context.Context) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for ctx.Err() == nil {
+			x, err := q.PopWait(ctx)
+			if err != nil || !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+// PopBatch removes and returns up to maxItems elements from the front of the queue.
+// It waits until at least one element is available, and then returns as soon as
+// maxItems elements are available or maxWait has elapsed since it saw the first one,
+// trading latency for larger batches. If the queue is closed, PopBatch returns the
+// remaining elements without waiting, or ErrClosed if there are none.
+// If ctx is done before it returns elements, PopBatch returns the error of ctx.
+// If maxItems is not positive, PopBatch panics.
+func (q *SyncQueue[T]) PopBatch(ctx context.Context, maxItems int, maxWait time.Duration) ([]T, error) {
+	if maxItems <= 0 {
+		panic(fmt.Sprintf("queue: batch size must be positive: %d", maxItems))
+	}
+
+	var timer *time.Timer
+	var timeout <-chan time.Time
+	expired := false
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		q.mu.Lock()
+		n := q.queue.Len()
+		if n >= maxItems || (n > 0 && (q.closed || expired)) {
+			batch := make([]T, min(n, maxItems))
+			q.popManyLocked(batch)
+			q.mu.Unlock()
+			return batch, nil
+		}
+		if q.closed {
+			q.mu.Unlock()
+			return nil, ErrClosed
+		}
+		var wake <-chan struct{}
+		if n == 0 {
+			wake = q.readyLocked()
+		} else {
+			wake = q.changedLocked()
+			if timer == nil {
+				timer = time.NewTimer(maxWait)
+				timeout = timer.C
+			}
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-timeout:
+			expired = true
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// The delays between retries of a failed batch in Consume.
+const (
+	consumeMinRetryDelay = 10 * time.Millisecond
+	consumeMaxRetryDelay = time.Second
+)
+
+// Consume repeatedly pops up to batchSize elements and calls fn with them.
+// It waits while the queue is empty, and returns nil once the queue is closed and drained.
+// If fn returns an error, the same batch is passed to fn again after an exponential backoff
+// until fn succeeds. If ctx is done, Consume returns the error of ctx; a batch that has not
+// been processed successfully is put back to the front of the queue.
+// fn must not retain the slice after it returns.
+// If batchSize is not positive, Consume panics.
+func (q *SyncQueue[T]) Consume(ctx context.Context, batchSize int, fn func([]T) error) error {
+	if batchSize <= 0 {
+		panic(fmt.Sprintf("queue: batch size must be positive: %d", batchSize))
+	}
+
+	batch := make([]T, batchSize)
+	for {
+		n, err := q.popBatchWait(ctx, batch)
+		if err == ErrClosed {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		delay := consumeMinRetryDelay
+		for fn(batch[:n]) != nil {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				q.unpop(batch[:n])
+				return ctx.Err()
+			case <-timer.C:
+			}
+			delay = min(2*delay, consumeMaxRetryDelay)
+		}
+		clear(batch[:n])
+	}
+}
+
+// Peek returns the element at the front of the queue without removing it.
+// If the queue is empty, Peek returns the zero value of T and false.
+func (q *SyncQueue[T]) Peek() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Peek()
+}
+
+// NotEmpty returns a channel that is closed when the queue is not empty or has been closed.
+// Consumers can select on it together with other channels and then call Pop or PopWait,
+// which may still find the queue empty if another consumer took the element first.
+// The returned channel reflects the state at the time of the call;
+// call NotEmpty again after the queue becomes empty.
+func (q *SyncQueue[T]) NotEmpty() <-chan struct{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.readyLocked()
+}
+
+// popLocked pops an element and updates the readiness. Caller must hold mu.
+func (q *SyncQueue[T]) popLocked() (T, bool) {
+	x, ok := q.queue.Pop()
+	if ok && q.queue.IsEmpty() && !q.closed {
+		// The closed channel no longer reflects the state. A new one is made on demand.
+		q.ready = nil
+	}
+	return x, ok
+}
+
+// popBatchWait pops up to len(dst) elements into dst, waiting until at least one is available,
+// and returns the number of elements popped. Unlike PopWait, it fails if ctx is already done
+// even when elements are available. Caller must not hold mu.
+func (q *SyncQueue[T]) popBatchWait(ctx context.Context, dst []T) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	x, err := q.PopWait(ctx)
+	if err != nil {
+		return 0, err
+	}
+	dst[0] = x
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return 1 + q.popManyLocked(dst[1:]), nil
+}
+
+// popManyLocked pops elements into dst and updates the readiness.
+// It returns the number of elements popped. Caller must hold mu.
+func (q *SyncQueue[T]) popManyLocked(dst []T) int {
+	n := q.queue.popInto(dst)
+	if n > 0 && q.queue.IsEmpty() && !q.closed {
+		q.ready = nil
+	}
+	return n
+}
+
+// unpop puts back popped elements to the front of the queue, even if the queue is closed.
+func (q *SyncQueue[T]) unpop(xs []T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.queue.InsertMany(0, xs)
+	q.notifyLocked()
+}
+
+// changedLocked returns a channel that is closed on the next push or close.
+// Caller must hold mu.
+func (q *SyncQueue[T]) changedLocked() chan struct{} {
+	if q.changed == nil {
+		q.changed = make(chan struct{})
+	}
+	return q.changed
+}
+
+// readyLocked returns the channel that is closed when the queue is not empty.
+// Caller must hold mu.
+func (q *SyncQueue[T]) readyLocked() chan struct{} {
+	if q.ready == nil {
+		q.ready = make(chan struct{})
+		if !q.queue.IsEmpty() || q.closed {
+			close(q.ready)
+		}
+	}
+	return q.ready
+}
+
+// notifyLocked wakes up the goroutines waiting for a push or close,
+// and closes the ready channel if the queue has become non-empty or closed.
+// Caller must hold mu.
+func (q *SyncQueue[T]) notifyLocked() {
+	if q.changed != nil {
+		close(q.changed)
+		q.changed = nil
+	}
+	if q.ready == nil || (q.queue.IsEmpty() && !q.closed) {
+		return
+	}
+	select {
+	case <-q.ready:
+		// already closed
+	default:
+		close(q.ready)
+	}
+}
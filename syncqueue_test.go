@@ -0,0 +1,333 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestSyncQueue_NotEmpty(t *testing.T) {
+	var q queue.SyncQueue[int]
+
+	ready := q.NotEmpty()
+	select {
+	case <-ready:
+		t.Fatalf("NotEmpty() is ready on an empty queue")
+	default:
+	}
+
+	q.Push(1)
+	select {
+	case <-ready:
+	case <-time.After(time.Second):
+		t.Fatalf("NotEmpty() is not ready after Push")
+	}
+
+	q.Pop()
+	select {
+	case <-q.NotEmpty():
+		t.Fatalf("NotEmpty() is ready after the queue became empty again")
+	default:
+	}
+}
+
+func TestSyncQueue_PopWait(t *testing.T) {
+	var q queue.SyncQueue[int]
+	const producers, perProducer = 4, 1000
+
+	var wg sync.WaitGroup
+	for p := range producers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perProducer {
+				q.Push(p*perProducer + i)
+			}
+		}()
+	}
+
+	seen := make(map[int]bool)
+	for range producers * perProducer {
+		x, err := q.PopWait(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[x] {
+			t.Fatalf("PopWait() returned %v twice", x)
+		}
+		seen[x] = true
+	}
+	wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.PopWait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("PopWait() on empty queue = %v; want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestSyncQueue_Close(t *testing.T) {
+	var q queue.SyncQueue[int]
+	q.Push(1)
+	q.Push(2)
+
+	// Start a consumer blocked on an empty queue after draining.
+	done := make(chan []int)
+	go func() {
+		var got []int
+		for {
+			x, err := q.PopWait(context.Background())
+			if err == queue.ErrClosed {
+				done <- got
+				return
+			}
+			got = append(got, x)
+		}
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	q.Close()
+
+	select {
+	case got := <-done:
+		if !slices.Equal(got, []int{1, 2}) {
+			t.Errorf("drained %v; want %v", got, []int{1, 2})
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("PopWait() did not return after Close")
+	}
+
+	if err := q.Push(3); err != queue.ErrClosed {
+		t.Errorf("Push() after Close = %v; want %v", err, queue.ErrClosed)
+	}
+	if err := q.PushMany([]int{3, 4}); err != queue.ErrClosed {
+		t.Errorf("PushMany() after Close = %v; want %v", err, queue.ErrClosed)
+	}
+	if _, err := q.PopWait(context.Background()); err != queue.ErrClosed {
+		t.Errorf("PopWait() after Close = %v; want %v", err, queue.ErrClosed)
+	}
+	select {
+	case <-q.NotEmpty():
+	default:
+		t.Errorf("NotEmpty() is not ready after Close")
+	}
+}
+
+func TestSyncQueue_Consume(t *testing.T) {
+	// Setup
+	var q queue.SyncQueue[int]
+	q.PushMany([]int{1, 2, 3, 4, 5, 6, 7})
+	q.Close()
+
+	// Exercise
+	var batches [][]int
+	failures := 1
+	err := q.Consume(context.Background(), 3, func(batch []int) error {
+		if failures > 0 {
+			failures--
+			return errors.New("temporary failure")
+		}
+		batches = append(batches, slices.Clone(batch))
+		return nil
+	})
+
+	// Verify
+	if err != nil {
+		t.Fatalf("Consume() = %v; want nil", err)
+	}
+	want := [][]int{{1, 2, 3}, {4, 5, 6}, {7}}
+	if !slices.EqualFunc(batches, want, slices.Equal) {
+		t.Errorf("batches = %v; want %v", batches, want)
+	}
+}
+
+func TestSyncQueue_ConsumeCanceled(t *testing.T) {
+	// Setup
+	var q queue.SyncQueue[int]
+	q.PushMany([]int{1, 2, 3})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Exercise
+	err := q.Consume(ctx, 2, func(batch []int) error {
+		cancel()
+		return errors.New("failure")
+	})
+
+	// Verify
+	if err != context.Canceled {
+		t.Errorf("Consume() = %v; want %v", err, context.Canceled)
+	}
+	// The failed batch is put back to the front.
+	var rest []int
+	for {
+		x, ok := q.Pop()
+		if !ok {
+			break
+		}
+		rest = append(rest, x)
+	}
+	if !slices.Equal(rest, []int{1, 2, 3}) {
+		t.Errorf("remaining elements = %v; want %v", rest, []int{1, 2, 3})
+	}
+}
+
+func TestSyncQueue_PopBatch(t *testing.T) {
+	t.Run("full batch", func(t *testing.T) {
+		var q queue.SyncQueue[int]
+		q.PushMany([]int{1, 2, 3, 4, 5})
+
+		batch, err := q.PopBatch(context.Background(), 3, time.Hour)
+
+		if err != nil || !slices.Equal(batch, []int{1, 2, 3}) {
+			t.Errorf("PopBatch() = (%v, %v); want ([1 2 3], nil)", batch, err)
+		}
+	})
+
+	t.Run("filled while waiting", func(t *testing.T) {
+		var q queue.SyncQueue[int]
+		go func() {
+			for i := range 4 {
+				time.Sleep(time.Millisecond)
+				q.Push(i)
+			}
+		}()
+
+		batch, err := q.PopBatch(context.Background(), 4, time.Hour)
+
+		if err != nil || !slices.Equal(batch, []int{0, 1, 2, 3}) {
+			t.Errorf("PopBatch() = (%v, %v); want ([0 1 2 3], nil)", batch, err)
+		}
+	})
+
+	t.Run("max wait", func(t *testing.T) {
+		var q queue.SyncQueue[int]
+		q.PushMany([]int{1, 2})
+
+		start := time.Now()
+		batch, err := q.PopBatch(context.Background(), 10, 20*time.Millisecond)
+
+		if err != nil || !slices.Equal(batch, []int{1, 2}) {
+			t.Errorf("PopBatch() = (%v, %v); want ([1 2], nil)", batch, err)
+		}
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+			t.Errorf("PopBatch() returned after %v; want at least 20ms", elapsed)
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		var q queue.SyncQueue[int]
+		q.Push(1)
+		q.Close()
+
+		batch, err := q.PopBatch(context.Background(), 10, time.Hour)
+		if err != nil || !slices.Equal(batch, []int{1}) {
+			t.Errorf("PopBatch() = (%v, %v); want ([1], nil)", batch, err)
+		}
+		if _, err := q.PopBatch(context.Background(), 10, time.Hour); err != queue.ErrClosed {
+			t.Errorf("PopBatch() on drained queue = %v; want %v", err, queue.ErrClosed)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		var q queue.SyncQueue[int]
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if _, err := q.PopBatch(ctx, 10, time.Hour); err != context.DeadlineExceeded {
+			t.Errorf("PopBatch() = %v; want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
+
+func TestSyncQueue_Drain(t *testing.T) {
+	t.Run("closed", func(t *testing.T) {
+		var q queue.SyncQueue[int]
+		go func() {
+			for i := range 100 {
+				q.Push(i)
+			}
+			q.Close()
+		}()
+
+		var got []int
+		for x := range q.Drain(context.Background()) {
+			got = append(got, x)
+		}
+
+		if len(got) != 100 || got[0] != 0 || got[99] != 99 {
+			t.Errorf("Drain() yielded %v elements; want 0..99", len(got))
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		var q queue.SyncQueue[int]
+		q.PushMany([]int{1, 2, 3})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var got []int
+		for x := range q.Drain(ctx) {
+			got = append(got, x)
+			if x == 2 {
+				cancel()
+			}
+		}
+
+		if !slices.Equal(got, []int{1, 2}) {
+			t.Errorf("Drain() yielded %v; want %v", got, []int{1, 2})
+		}
+		if q.Len() != 1 {
+			t.Errorf("Len() = %v; want 1", q.Len())
+		}
+	})
+}
+
+func TestSyncQueue_PushFromChan(t *testing.T) {
+	t.Run("channel closed", func(t *testing.T) {
+		var q queue.SyncQueue[int]
+		ch := make(chan int, 10)
+		go func() {
+			for i := range 100 {
+				ch <- i
+			}
+			close(ch)
+		}()
+
+		err := q.PushFromChan(context.Background(), ch)
+
+		if err != nil {
+			t.Fatalf("PushFromChan() = %v; want nil", err)
+		}
+		for i := range 100 {
+			if x, ok := q.Pop(); !ok || x != i {
+				t.Fatalf("Pop() = (%v, %v); want (%v, true)", x, ok, i)
+			}
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		var q queue.SyncQueue[int]
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if err := q.PushFromChan(ctx, make(chan int)); err != context.DeadlineExceeded {
+			t.Errorf("PushFromChan() = %v; want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("queue closed", func(t *testing.T) {
+		var q queue.SyncQueue[int]
+		q.Close()
+		ch := make(chan int, 1)
+		ch <- 1
+
+		if err := q.PushFromChan(context.Background(), ch); err != queue.ErrClosed {
+			t.Errorf("PushFromChan() = %v; want %v", err, queue.ErrClosed)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package queue
+
+// Tx is a transaction on a queue. Pushes and pops made through a transaction are applied
+// to the queue immediately, and Rollback restores the queue to the state at Begin.
+// The queue must not be modified except through the transaction until it is committed
+// or rolled back; the transaction panics if it detects such a modification.
+type Tx[T any] struct {
+	q *Queue[T]
+
+	// The length of the queue at Begin.
+	length int
+
+	// The elements popped in the transaction, in FIFO order.
+	popped []T
+
+	// The version of the queue after the last operation of the transaction.
+	version uint
+
+	done bool
+}
+
+// Begin starts a transaction on the queue.
+func (q *Queue[T]) Begin() *Tx[T] {
+	return &Tx[T]{q: q, length: q.length, version: q.version}
+}
+
+// Atomically runs f in a transaction. If f returns nil, the transaction is committed.
+// If f returns an error or panics, the transaction is rolled back and the error
+// is returned or the panic is propagated.
+func (q *Queue[T]) Atomically(f func(tx *Tx[T]) error) error {
+	tx := q.Begin()
+	defer func() {
+		// f returned an error or panicked.
+		if !tx.done {
+			tx.Rollback()
+		}
+	}()
+
+	if err := f(tx); err != nil {
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+// Len returns the number of elements in the queue.
+func (tx *Tx[T]) Len() int {
+	tx.check()
+	return tx.q.Len()
+}
+
+// Push adds an element to the back of the queue.
+func (tx *Tx[T]) Push(x T) {
+	tx.check()
+	tx.q.Push(x)
+	tx.version = tx.q.version
+}
+
+// Pop removes and returns the element at the front of the queue.
+// If the queue is empty, Pop returns the zero value of T and false.
+func (tx *Tx[T]) Pop() (T, bool) {
+	tx.check()
+	x, ok := tx.q.Pop()
+	if ok {
+		tx.popped = append(tx.popped, x)
+		tx.version = tx.q.version
+	}
+	return x, ok
+}
+
+// Peek returns the element at the front of the queue without removing it.
+// If the queue is empty, Peek returns the zero value of T and false.
+func (tx *Tx[T]) Peek() (T, bool) {
+	tx.check()
+	return tx.q.Peek()
+}
+
+// Commit ends the transaction, keeping its changes.
+func (tx *Tx[T]) Commit() {
+	tx.check()
+	tx.done = true
+	tx.popped = nil
+}
+
+// Rollback ends the transaction, restoring the elements of the queue to the state at Begin.
+func (tx *Tx[T]) Rollback() {
+	tx.check()
+	tx.done = true
+
+	// The queue holds the elements at Begin followed by the pushed ones,
+	// except for the first len(popped) elements.
+	p := len(tx.popped)
+	tx.q.Truncate(max(tx.length-p, 0))
+	tx.q.InsertMany(0, tx.popped[:min(p, tx.length)])
+	tx.popped = nil
+}
+
+// check panics if the transaction has ended or the queue has been modified outside it.
+func (tx *Tx[T]) check() {
+	if tx.done {
+		panic("queue: transaction has already been committed or rolled back")
+	}
+	if tx.q.version != tx.version {
+		panic("queue: queue modified outside the transaction")
+	}
+}
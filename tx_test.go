@@ -0,0 +1,98 @@
+package queue_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestQueue_Atomically(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	testCases := []struct {
+		title    string
+		pops     int
+		pushes   []int
+		err      error
+		expected []int
+	}{
+		{
+			title:    "commit",
+			pops:     2,
+			pushes:   []int{7, 8},
+			err:      nil,
+			expected: []int{4, 1, 5, 7, 8},
+		},
+		{
+			title:    "rollback",
+			pops:     2,
+			pushes:   []int{7, 8},
+			err:      errFailed,
+			expected: []int{3, 1, 4, 1, 5},
+		},
+		{
+			title:    "rollback after popping pushed elements",
+			pops:     6,
+			pushes:   []int{7, 8},
+			err:      errFailed,
+			expected: []int{3, 1, 4, 1, 5},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			// Setup
+			q := newQueue(5, 3, 1, 4, 1, 5)
+
+			// Exercise
+			err := q.Atomically(func(tx *queue.Tx[int]) error {
+				for _, x := range tc.pushes {
+					tx.Push(x)
+				}
+				for range tc.pops {
+					tx.Pop()
+				}
+				return tc.err
+			})
+
+			// Verify
+			if err != tc.err {
+				t.Errorf("Atomically() = %v; want %v", err, tc.err)
+			}
+			if actual := slices.Collect(q.All()); !slices.Equal(actual, tc.expected) {
+				t.Errorf("actual: %v; want: %v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestQueue_Atomically_panic(t *testing.T) {
+	q := newQueue(0, 3, 1, 4)
+
+	func() {
+		defer func() { recover() }()
+		q.Atomically(func(tx *queue.Tx[int]) error {
+			tx.Pop()
+			panic("boom")
+		})
+	}()
+
+	if actual := slices.Collect(q.All()); !slices.Equal(actual, []int{3, 1, 4}) {
+		t.Errorf("actual: %v; want: %v", actual, []int{3, 1, 4})
+	}
+}
+
+func TestTx_modifiedOutside(t *testing.T) {
+	q := newQueue(0, 3, 1, 4)
+	tx := q.Begin()
+	q.Push(1)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Tx did not panic on modification outside the transaction")
+		}
+	}()
+	tx.Pop()
+}
@@ -0,0 +1,60 @@
+package queue
+
+import "iter"
+
+// QueueView is a read-only view of a queue.
+// It lets observers inspect a queue without being able to modify it.
+type QueueView[T any] interface {
+	// Len returns the number of elements in the queue.
+	Len() int
+
+	// IsEmpty returns true if the queue is empty.
+	IsEmpty() bool
+
+	// Peek returns the element at the front of the queue.
+	// If the queue is empty, Peek returns the zero value of T and false.
+	Peek() (T, bool)
+
+	// At returns the element at the specified index. If the index is out of range, it panics.
+	At(i int) T
+
+	// All returns an iterator over all elements in the queue in FIFO order.
+	All() iter.Seq[T]
+
+	// Backward returns an iterator over all elements in the queue in reverse order.
+	Backward() iter.Seq[T]
+}
+
+// View returns a read-only view of the queue. The view reflects later modifications
+// of the queue, and it cannot be converted back to the queue by a type assertion.
+func (q *Queue[T]) View() QueueView[T] {
+	return queueView[T]{q: q}
+}
+
+type queueView[T any] struct {
+	q *Queue[T]
+}
+
+func (v queueView[T]) Len() int {
+	return v.q.Len()
+}
+
+func (v queueView[T]) IsEmpty() bool {
+	return v.q.IsEmpty()
+}
+
+func (v queueView[T]) Peek() (T, bool) {
+	return v.q.Peek()
+}
+
+func (v queueView[T]) At(i int) T {
+	return v.q.At(i)
+}
+
+func (v queueView[T]) All() iter.Seq[T] {
+	return v.q.All()
+}
+
+func (v queueView[T]) Backward() iter.Seq[T] {
+	return v.q.Backward()
+}
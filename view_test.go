@@ -0,0 +1,37 @@
+package queue_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestQueue_View(t *testing.T) {
+	// Setup
+	q := newQueue(5, 3, 1, 4)
+	v := q.View()
+
+	// Exercise
+	q.Push(1)
+
+	// Verify
+	if v.Len() != 4 || v.IsEmpty() {
+		t.Errorf("Len() = %v, IsEmpty() = %v; want 4, false", v.Len(), v.IsEmpty())
+	}
+	if x, ok := v.Peek(); !ok || x != 3 {
+		t.Errorf("Peek() = (%v, %v); want (3, true)", x, ok)
+	}
+	if x := v.At(2); x != 4 {
+		t.Errorf("At(2) = %v; want 4", x)
+	}
+	if actual, want := slices.Collect(v.All()), []int{3, 1, 4, 1}; !slices.Equal(actual, want) {
+		t.Errorf("All() = %v; want %v", actual, want)
+	}
+	if actual, want := slices.Collect(v.Backward()), []int{1, 4, 1, 3}; !slices.Equal(actual, want) {
+		t.Errorf("Backward() = %v; want %v", actual, want)
+	}
+	if _, ok := v.(*queue.Queue[int]); ok {
+		t.Errorf("View() can be converted back to *Queue")
+	}
+}
@@ -0,0 +1,73 @@
+package queue
+
+import "fmt"
+
+// watermarks holds the high and low watermarks set by SetWatermarks.
+type watermarks struct {
+	low, high     int
+	onHigh, onLow func(length int)
+
+	// Whether the length has reached high and has not fallen to low since.
+	above bool
+}
+
+// SetWatermarks sets the high and low watermarks of the length for backpressure.
+// When the length reaches high, onHigh is called with the length, and the queue is considered
+// above the watermark until the length falls to low or below, when onLow is called.
+// The gap between the two marks prevents the callbacks from firing on every push and pop
+// around a single threshold. Either callback may be nil; use IsAboveWatermark to poll the state.
+// The callbacks are called in the middle of the operation that changed the length,
+// so they must not modify the queue.
+// The current length only sets the initial state; no callback is called by SetWatermarks itself.
+// It replaces the watermarks set before. If 0 <= low < high does not hold, it panics.
+func (q *Queue[T]) SetWatermarks(low, high int, onHigh, onLow func(length int)) {
+	if low < 0 || low >= high {
+		panic(fmt.Sprintf("queue: invalid watermarks: low=%d, high=%d", low, high))
+	}
+	q.ensureExtensions().watermarks = &watermarks{
+		low:    low,
+		high:   high,
+		onHigh: onHigh,
+		onLow:  onLow,
+		above:  q.length >= high,
+	}
+}
+
+// RemoveWatermarks removes the watermarks set by SetWatermarks.
+func (q *Queue[T]) RemoveWatermarks() {
+	if q.ext != nil {
+		q.ext.watermarks = nil
+	}
+}
+
+// IsAboveWatermark reports whether the length has reached the high watermark
+// and has not fallen to the low watermark since.
+// If no watermarks are set, it returns false.
+func (q *Queue[T]) IsAboveWatermark() bool {
+	return q.ext != nil && q.ext.watermarks != nil && q.ext.watermarks.above
+}
+
+// checkWatermarks calls the watermark callbacks if the length has crossed a watermark.
+// Caller must call it after updating length.
+func (q *Queue[T]) checkWatermarks() {
+	if q.ext == nil || q.ext.watermarks == nil {
+		return
+	}
+	w := q.ext.watermarks
+	switch {
+	case !w.above && q.length >= w.high:
+		w.above = true
+		if w.onHigh != nil {
+			op, version := q.beginCallback()
+			w.onHigh(q.length)
+			q.endCallback("onHigh", op, version)
+		}
+	case w.above && q.length <= w.low:
+		w.above = false
+		if w.onLow != nil {
+			op, version := q.beginCallback()
+			w.onLow(q.length)
+			q.endCallback("onLow", op, version)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package queue_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestQueue_SetWatermarks(t *testing.T) {
+	// Setup
+	var q queue.Queue[int]
+	var events []string
+	q.SetWatermarks(2, 5,
+		func(n int) { events = append(events, fmt.Sprintf("high %d", n)) },
+		func(n int) { events = append(events, fmt.Sprintf("low %d", n)) },
+	)
+
+	// Exercise & Verify
+	for i := range 4 {
+		q.Push(i)
+	}
+	if len(events) != 0 || q.IsAboveWatermark() {
+		t.Errorf("events = %v, IsAboveWatermark() = %v below the high watermark", events, q.IsAboveWatermark())
+	}
+
+	q.PushMany([]int{4, 5, 6})
+	q.Pop()
+	q.Push(7)
+	if !q.IsAboveWatermark() {
+		t.Errorf("IsAboveWatermark() = false after reaching the high watermark")
+	}
+
+	q.Pop()
+	q.Pop()
+	q.Pop()
+	q.Truncate(2)
+	q.Push(8)
+	q.Reset()
+
+	// Verify
+	if want := []string{"high 7", "low 2"}; !slices.Equal(events, want) {
+		t.Errorf("events = %v; want %v", events, want)
+	}
+	if q.IsAboveWatermark() {
+		t.Errorf("IsAboveWatermark() = true after falling to the low watermark")
+	}
+
+	// Exercise
+	q.RemoveWatermarks()
+	q.PushMany([]int{1, 2, 3, 4, 5, 6})
+
+	// Verify
+	if len(events) != 2 {
+		t.Errorf("events = %v after RemoveWatermarks", events)
+	}
+}
@@ -0,0 +1,117 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// WorkerPool runs a fixed number of goroutines that pop elements from a SyncQueue
+// and pass them to a handler.
+// A panic in the handler is recovered so that the worker keeps running.
+// It is logged with the standard logger unless OnPanic registers another function.
+type WorkerPool[T any] struct {
+	queue   *SyncQueue[T]
+	workers int
+	handler func(ctx context.Context, x T)
+	onPanic func(x T, v any)
+
+	// The context passed to the handler, canceled when Shutdown gives up waiting.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Whether Start has been called. Shutdown only waits for the workers if it has.
+	started atomic.Bool
+
+	wg   sync.WaitGroup
+	done chan struct{}
+}
+
+// NewWorkerPool returns a pool of n workers that consume q with handler.
+// The workers do not run until Start is called. If n is not positive, it panics.
+func NewWorkerPool[T any](q *SyncQueue[T], n int, handler func(ctx context.Context, x T)) *WorkerPool[T] {
+	if n <= 0 {
+		panic(fmt.Sprintf("queue: number of workers must be positive: %d", n))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WorkerPool[T]{
+		queue:   q,
+		workers: n,
+		handler: handler,
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+}
+
+// OnPanic registers a function called with the element and the recovered value
+// when the handler panics, instead of logging the panic. It must be called before Start.
+func (p *WorkerPool[T]) OnPanic(f func(x T, v any)) {
+	p.onPanic = f
+}
+
+// Start starts the workers. It must be called only once.
+func (p *WorkerPool[T]) Start() {
+	p.started.Store(true)
+	p.wg.Add(p.workers)
+	for range p.workers {
+		go p.run()
+	}
+	go func() {
+		p.wg.Wait()
+		close(p.done)
+	}()
+}
+
+// Shutdown closes the queue and waits until the workers have processed all remaining elements.
+// If ctx is done before that, Shutdown cancels the context passed to the handler,
+// waits for the in-flight handlers to return, and returns the error of ctx;
+// elements not yet popped are left in the queue.
+// If Start has not been called, Shutdown closes the queue and returns nil immediately.
+func (p *WorkerPool[T]) Shutdown(ctx context.Context) error {
+	p.queue.Close()
+	if !p.started.Load() {
+		p.cancel()
+		return nil
+	}
+	select {
+	case <-p.done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-p.done
+		return ctx.Err()
+	}
+}
+
+func (p *WorkerPool[T]) run() {
+	defer p.wg.Done()
+	for p.ctx.Err() == nil {
+		x, err := p.queue.PopWait(p.ctx)
+		if err != nil {
+			return
+		}
+		p.handle(x)
+	}
+}
+
+func (p *WorkerPool[T]) handle(x T) {
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+		if p.onPanic != nil {
+			p.onPanic(x, v)
+			return
+		}
+		stack := make([]byte, 64<<10)
+		stack = stack[:runtime.Stack(stack, false)]
+		log.Printf("queue: WorkerPool handler panicked: %v\n%s", v, stack)
+	}()
+	p.handler(p.ctx, x)
+}
@@ -0,0 +1,128 @@
+package queue_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestWorkerPool(t *testing.T) {
+	// Setup
+	var q queue.SyncQueue[int]
+	var sum atomic.Int64
+	var mu sync.Mutex
+	var panicked []int
+	p := queue.NewWorkerPool(&q, 4, func(ctx context.Context, x int) {
+		if x%10 == 0 {
+			panic("boom")
+		}
+		sum.Add(int64(x))
+	})
+	p.OnPanic(func(x int, v any) {
+		mu.Lock()
+		defer mu.Unlock()
+		panicked = append(panicked, x)
+	})
+	p.Start()
+
+	// Exercise
+	want := 0
+	for i := 1; i <= 100; i++ {
+		q.Push(i)
+		if i%10 != 0 {
+			want += i
+		}
+	}
+	err := p.Shutdown(context.Background())
+
+	// Verify
+	if err != nil {
+		t.Fatalf("Shutdown() = %v; want nil", err)
+	}
+	if sum.Load() != int64(want) {
+		t.Errorf("sum = %v; want %v", sum.Load(), want)
+	}
+	if len(panicked) != 10 {
+		t.Errorf("recovered %v panics; want 10", len(panicked))
+	}
+	if q.Len() != 0 {
+		t.Errorf("Len() = %v; want 0", q.Len())
+	}
+}
+
+func TestWorkerPool_ShutdownTimeout(t *testing.T) {
+	// Setup
+	var q queue.SyncQueue[int]
+	started := make(chan struct{})
+	p := queue.NewWorkerPool(&q, 1, func(ctx context.Context, x int) {
+		close(started)
+		<-ctx.Done()
+	})
+	q.PushMany([]int{1, 2, 3})
+	p.Start()
+	<-started
+
+	// Exercise
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := p.Shutdown(ctx)
+
+	// Verify
+	if err != context.DeadlineExceeded {
+		t.Errorf("Shutdown() = %v; want %v", err, context.DeadlineExceeded)
+	}
+	if q.Len() != 2 {
+		t.Errorf("Len() = %v; want 2", q.Len())
+	}
+}
+
+func TestWorkerPool_ShutdownWithoutStart(t *testing.T) {
+	// Setup
+	var q queue.SyncQueue[int]
+	p := queue.NewWorkerPool(&q, 2, func(ctx context.Context, x int) {})
+	q.Push(1)
+
+	// Exercise
+	err := p.Shutdown(context.Background())
+
+	// Verify
+	if err != nil {
+		t.Errorf("Shutdown() = %v; want nil", err)
+	}
+	if !errors.Is(q.Push(2), queue.ErrClosed) {
+		t.Errorf("Push() after Shutdown did not return ErrClosed")
+	}
+}
+
+func TestWorkerPool_PanicLoggedByDefault(t *testing.T) {
+	// Setup
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	var q queue.SyncQueue[int]
+	p := queue.NewWorkerPool(&q, 1, func(ctx context.Context, x int) {
+		panic("boom")
+	})
+	q.Push(1)
+
+	// Exercise
+	p.Start()
+	err := p.Shutdown(context.Background())
+
+	// Verify
+	if err != nil {
+		t.Fatalf("Shutdown() = %v; want nil", err)
+	}
+	if !strings.Contains(buf.String(), "boom") {
+		t.Errorf("log output = %q; want it to contain the panic value", buf.String())
+	}
+}
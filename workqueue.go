@@ -0,0 +1,125 @@
+package queue
+
+import "time"
+
+// Receipt identifies an element handed out by WorkQueue.Pop.
+type Receipt uint64
+
+// WorkQueue is a FIFO work queue that tracks in-flight elements, like a small in-process SQS.
+// Pop hands out an element together with a receipt. The consumer acknowledges the element
+// with Ack when it has been processed, or returns it to the front of the queue with Nack.
+// An element that is neither acknowledged nor returned within the visibility timeout
+// is returned to the front of the queue automatically.
+// WorkQueue is NOT safe for concurrent use.
+type WorkQueue[T any] struct {
+	pending Queue[T]
+	timeout time.Duration
+
+	// The in-flight elements by receipt.
+	inflight map[Receipt]inflightElement[T]
+
+	// The receipts of the in-flight elements in the order of their deadlines.
+	// Since the timeout is constant, this is the order in which they were popped.
+	// It may contain receipts that have already been acknowledged or returned.
+	deadlines Queue[Receipt]
+
+	lastReceipt Receipt
+}
+
+// inflightElement is an element handed out by Pop and not yet acknowledged.
+type inflightElement[T any] struct {
+	value    T
+	deadline time.Time
+}
+
+// NewWorkQueue returns an empty work queue with the given visibility timeout.
+func NewWorkQueue[T any](timeout time.Duration) *WorkQueue[T] {
+	return &WorkQueue[T]{
+		timeout:  timeout,
+		inflight: make(map[Receipt]inflightElement[T]),
+	}
+}
+
+// Len returns the number of elements waiting to be popped.
+// Elements whose visibility timeout has expired are counted after the next Pop, Ack or Nack.
+func (q *WorkQueue[T]) Len() int {
+	return q.pending.Len()
+}
+
+// InFlight returns the number of elements popped but not yet acknowledged or returned.
+func (q *WorkQueue[T]) InFlight() int {
+	return len(q.inflight)
+}
+
+// Push adds an element to the back of the queue.
+func (q *WorkQueue[T]) Push(x T) {
+	q.pending.Push(x)
+}
+
+// Pop hands out the element at the front of the queue with a receipt for Ack and Nack.
+// It first returns the in-flight elements whose visibility timeout has expired to the front.
+// If the queue is empty, Pop returns the zero value of T, zero and false.
+func (q *WorkQueue[T]) Pop() (T, Receipt, bool) {
+	now := time.Now()
+	q.expire(now)
+
+	x, ok := q.pending.Pop()
+	if !ok {
+		return x, 0, false
+	}
+
+	q.lastReceipt++
+	r := q.lastReceipt
+	q.inflight[r] = inflightElement[T]{value: x, deadline: now.Add(q.timeout)}
+	q.deadlines.Push(r)
+	return x, r, true
+}
+
+// Ack marks the element as processed and forgets it.
+// It returns false if the receipt is unknown, for example because the element
+// has already been acknowledged or its visibility timeout has expired.
+func (q *WorkQueue[T]) Ack(r Receipt) bool {
+	// Expire first so that a late Ack does not delete an element that must be redelivered.
+	q.expire(time.Now())
+	if _, ok := q.inflight[r]; !ok {
+		return false
+	}
+	delete(q.inflight, r)
+	return true
+}
+
+// Nack returns the element to the front of the queue immediately.
+// It returns false if the receipt is unknown, including when the visibility timeout
+// has expired and the element has already been returned to the queue.
+func (q *WorkQueue[T]) Nack(r Receipt) bool {
+	q.expire(time.Now())
+	e, ok := q.inflight[r]
+	if !ok {
+		return false
+	}
+	delete(q.inflight, r)
+	q.pending.InsertAt(0, e.value)
+	return true
+}
+
+// expire returns the in-flight elements whose deadline is not after now
+// to the front of the queue, keeping the order in which they were popped.
+func (q *WorkQueue[T]) expire(now time.Time) {
+	var expired []T
+	for {
+		r, ok := q.deadlines.Peek()
+		if !ok {
+			break
+		}
+		e, inflight := q.inflight[r]
+		if inflight && e.deadline.After(now) {
+			break
+		}
+		q.deadlines.Pop()
+		if inflight {
+			delete(q.inflight, r)
+			expired = append(expired, e.value)
+		}
+	}
+	q.pending.InsertMany(0, expired)
+}
@@ -0,0 +1,87 @@
+package queue_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nojima/queue-go"
+)
+
+func TestWorkQueue_AckNack(t *testing.T) {
+	q := queue.NewWorkQueue[string](time.Hour)
+	q.Push("a")
+	q.Push("b")
+	q.Push("c")
+
+	_, ra, _ := q.Pop()
+	_, rb, _ := q.Pop()
+	if q.Len() != 1 || q.InFlight() != 2 {
+		t.Errorf("Len() = %v, InFlight() = %v; want 1, 2", q.Len(), q.InFlight())
+	}
+
+	if !q.Ack(ra) {
+		t.Errorf("Ack(a) = false; want true")
+	}
+	if q.Ack(ra) {
+		t.Errorf("second Ack(a) = true; want false")
+	}
+	if !q.Nack(rb) {
+		t.Errorf("Nack(b) = false; want true")
+	}
+
+	// "b" is back at the front.
+	if x, _, ok := q.Pop(); x != "b" || !ok {
+		t.Errorf("Pop() = %q, %v; want %q, %v", x, ok, "b", true)
+	}
+	if x, _, ok := q.Pop(); x != "c" || !ok {
+		t.Errorf("Pop() = %q, %v; want %q, %v", x, ok, "c", true)
+	}
+	if _, _, ok := q.Pop(); ok {
+		t.Errorf("Pop() on empty queue returned ok")
+	}
+}
+
+func TestWorkQueue_visibilityTimeout(t *testing.T) {
+	q := queue.NewWorkQueue[string](10 * time.Millisecond)
+	q.Push("a")
+	q.Push("b")
+	q.Push("c")
+
+	_, ra, _ := q.Pop()
+	_, rb, _ := q.Pop()
+	q.Ack(rb)
+	time.Sleep(20 * time.Millisecond)
+
+	// "a" has expired and is back at the front; "b" was acknowledged.
+	if x, _, ok := q.Pop(); x != "a" || !ok {
+		t.Errorf("Pop() = %q, %v; want %q, %v", x, ok, "a", true)
+	}
+	if q.Ack(ra) {
+		t.Errorf("Ack with an expired receipt = true; want false")
+	}
+	if x, _, ok := q.Pop(); x != "c" || !ok {
+		t.Errorf("Pop() = %q, %v; want %q, %v", x, ok, "c", true)
+	}
+}
+
+func TestWorkQueue_lateAck(t *testing.T) {
+	// Setup
+	q := queue.NewWorkQueue[string](10 * time.Millisecond)
+	q.Push("a")
+	_, r, _ := q.Pop()
+	time.Sleep(20 * time.Millisecond)
+
+	// Exercise: no Pop between the deadline and the Ack.
+	acked := q.Ack(r)
+
+	// Verify
+	if acked {
+		t.Errorf("Ack after the deadline = true; want false")
+	}
+	if q.Len() != 1 || q.InFlight() != 0 {
+		t.Errorf("Len(), InFlight() = %v, %v; want 1, 0", q.Len(), q.InFlight())
+	}
+	if x, _, ok := q.Pop(); x != "a" || !ok {
+		t.Errorf("Pop() = %q, %v; want %q, %v", x, ok, "a", true)
+	}
+}